@@ -0,0 +1,43 @@
+package matrix
+
+import "fmt"
+
+// Builder constructs the matrix row by row when count of rows is not known upfront.
+// Zero value of Builder is ready to use
+type Builder struct {
+	rows int
+	cols int
+	data []float64
+	err  error
+}
+
+// AppendRow appends copy of given values as the next row of the matrix
+func (b *Builder) AppendRow(values []float64) error {
+	if b.err != nil {
+		return b.err
+	}
+	if b.rows == 0 {
+		b.cols = len(values)
+	}
+	if len(values) != b.cols {
+		b.err = fmt.Errorf("Row %d has %d elements, expected %d", b.rows, len(values), b.cols)
+		return b.err
+	}
+	b.data = append(b.data, values...)
+	b.rows++
+	return nil
+}
+
+// Build returns pointer to the new matrix consisting of appended rows
+func (b *Builder) Build() (*Matrix, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	m := &Matrix{
+		rows: b.rows,
+		cols: b.cols,
+		data: make([]float64, b.rows*b.cols),
+	}
+	copy(m.data, b.data)
+	return m, nil
+}
@@ -0,0 +1,46 @@
+package matrix_test
+
+import (
+	"testing"
+
+	"github.com/andreipimenov/algebra/matrix"
+	"github.com/andreipimenov/algebra/matrix/matrixtest"
+)
+
+func TestBuilder(t *testing.T) {
+	b := &matrix.Builder{}
+	rows := [][]float64{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}, {10, 11, 12}}
+	for _, r := range rows {
+		if err := b.AppendRow(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rows[0][0] = 0
+	m, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}, {10, 11, 12}}), 0)
+}
+
+func TestBuilderRagged(t *testing.T) {
+	b := &matrix.Builder{}
+	b.AppendRow([]float64{1, 2})
+	if err := b.AppendRow([]float64{1, 2, 3}); err == nil {
+		t.Error("Expected error for ragged row")
+	}
+	if err := b.AppendRow([]float64{3, 4}); err == nil {
+		t.Error("Expected the first error after ragged row")
+	}
+	if _, err := b.Build(); err == nil {
+		t.Error("Expected error from Build after ragged row")
+	}
+}
+
+func TestBuilderEmpty(t *testing.T) {
+	m, err := (&matrix.Builder{}).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, m, newMatrix(t, 0, 0), 0)
+}
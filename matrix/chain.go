@@ -0,0 +1,85 @@
+package matrix
+
+// Chain applies sequence of operations to the copy of the matrix.
+// The first error stops applying further operations and is returned by Result
+type Chain struct {
+	m   *Matrix
+	err error
+}
+
+// Chain returns new chain of operations starting with the copy of the matrix
+func (m *Matrix) Chain() *Chain {
+	return &Chain{m: m.Clone()}
+}
+
+// Add adds the matrix
+func (c *Chain) Add(x *Matrix) *Chain {
+	if c.err == nil {
+		c.err = c.m.Add(x)
+	}
+	return c
+}
+
+// Sub subtracts the matrix
+func (c *Chain) Sub(x *Matrix) *Chain {
+	if c.err == nil {
+		c.err = c.m.Sub(x)
+	}
+	return c
+}
+
+// Addn adds number to every element
+func (c *Chain) Addn(n float64) *Chain {
+	if c.err == nil {
+		c.m.Addn(n)
+	}
+	return c
+}
+
+// Scale multiplies every element by number
+func (c *Chain) Scale(n float64) *Chain {
+	if c.err == nil {
+		c.m.Scale(n)
+	}
+	return c
+}
+
+// Hadamard multiplies every element by the corresponding element of the matrix
+func (c *Chain) Hadamard(x *Matrix) *Chain {
+	if c.err == nil {
+		c.err = c.m.Hadamard(x)
+	}
+	return c
+}
+
+// T transposes the matrix
+func (c *Chain) T() *Chain {
+	if c.err == nil {
+		c.m = c.m.T()
+	}
+	return c
+}
+
+// Mul multiplies by the matrix on the right
+func (c *Chain) Mul(x *Matrix) *Chain {
+	if c.err == nil {
+		c.m, c.err = c.m.Mul(x)
+	}
+	return c
+}
+
+// Inverse inverts the matrix
+func (c *Chain) Inverse() *Chain {
+	if c.err == nil {
+		c.m, c.err = c.m.Inverse()
+	}
+	return c
+}
+
+// Result returns the matrix after all operations or the first error occurred
+func (c *Chain) Result() (*Matrix, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return c.m, nil
+}
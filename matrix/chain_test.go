@@ -0,0 +1,48 @@
+package matrix_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/andreipimenov/algebra/matrix"
+	"github.com/andreipimenov/algebra/matrix/matrixtest"
+)
+
+func TestChain(t *testing.T) {
+	m := fromSlice(t, [][]float64{{1, 2}, {3, 4}})
+	r, err := m.Chain().
+		Add(m).
+		Sub(fromSlice(t, [][]float64{{1, 1}, {1, 1}})).
+		Scale(2).
+		Addn(1).
+		Hadamard(fromSlice(t, [][]float64{{1, 0}, {0, 1}})).
+		T().
+		Mul(fromSlice(t, [][]float64{{1}, {1}})).
+		Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, r, fromSlice(t, [][]float64{{3}, {15}}), 0)
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{1, 2}, {3, 4}}), 0)
+	inv, err := m.Chain().Inverse().Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := m.Inverse()
+	matrixtest.AssertEqual(t, inv, want, 0)
+}
+
+func TestChainError(t *testing.T) {
+	m := fromSlice(t, [][]float64{{1, 2}, {3, 4}})
+	r, err := m.Chain().Add(newMatrix(t, 3, 3)).Mul(nil).Sub(newMatrix(t, 1, 1)).Result()
+	if err == nil || !strings.Contains(err.Error(), "3x3") {
+		t.Errorf("Got error %v, want the first error naming 3x3", err)
+	}
+	if r != nil {
+		t.Errorf("Got %v with error, want nil", r)
+	}
+	if _, err := fromSlice(t, [][]float64{{1, 2}, {2, 4}}).Chain().Inverse().Add(m).Result(); !errors.Is(err, matrix.ErrSingular) {
+		t.Errorf("Got error %v, want %v", err, matrix.ErrSingular)
+	}
+}
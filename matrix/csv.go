@@ -0,0 +1,91 @@
+package matrix
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// WriteCSV writes the matrix to w as comma-separated values, one row per line
+func (m *Matrix) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	m.RLock()
+	defer m.RUnlock()
+	record := make([]string, m.cols)
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			record[j] = strconv.FormatFloat(m.data[m.cols*i+j], 'g', -1, 64)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ReadCSV returns pointer to the new matrix parsed from comma-separated values
+func ReadCSV(r io.Reader) (*Matrix, error) {
+	rr, err := NewRowReader(r)
+	if err != nil {
+		return nil, err
+	}
+	m := &Matrix{data: []float64{}}
+	for {
+		row, err := rr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		m.cols = len(row)
+		m.data = append(m.data, row...)
+		m.rows++
+	}
+	return m, nil
+}
+
+// RowReader reads rows of the matrix from comma-separated values one at a time
+type RowReader struct {
+	cr   *csv.Reader
+	cols int
+	rows int
+}
+
+// NewRowReader returns pointer to the new reader of rows from comma-separated values
+func NewRowReader(r io.Reader) (*RowReader, error) {
+	if r == nil {
+		return nil, errors.New("Reader must not be nil")
+	}
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	return &RowReader{cr: cr}, nil
+}
+
+// Next returns the next parsed row or io.EOF when there are no more rows
+func (rr *RowReader) Next() ([]float64, error) {
+	record, err := rr.cr.Read()
+	if err != nil {
+		return nil, err
+	}
+	line, _ := rr.cr.FieldPos(0)
+	if rr.rows == 0 {
+		rr.cols = len(record)
+	}
+	if len(record) != rr.cols {
+		return nil, fmt.Errorf("Line %d has %d values, expected %d", line, len(record), rr.cols)
+	}
+	row := make([]float64, len(record))
+	for j, s := range record {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Line %d has non-numeric value %q", line, s)
+		}
+		row[j] = v
+	}
+	rr.rows++
+	return row, nil
+}
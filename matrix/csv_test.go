@@ -0,0 +1,89 @@
+package matrix_test
+
+import (
+	"bytes"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/andreipimenov/algebra/matrix"
+	"github.com/andreipimenov/algebra/matrix/matrixtest"
+)
+
+func TestCSVRoundTrip(t *testing.T) {
+	m := fromSlice(t, [][]float64{{1.5, -2, 1.0 / 3}, {4e-300, 5e300, 0}})
+	b := &bytes.Buffer{}
+	if err := m.WriteCSV(b); err != nil {
+		t.Fatal(err)
+	}
+	r, err := matrix.ReadCSV(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.Equals(m) {
+		t.Errorf("Got %v after round trip, want %v", r, m)
+	}
+}
+
+func TestReadCSV(t *testing.T) {
+	m, err := matrix.ReadCSV(strings.NewReader("1,2\n3,4\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{1, 2}, {3, 4}}), 0)
+	m, err = matrix.ReadCSV(strings.NewReader(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, m, newMatrix(t, 0, 0), 0)
+}
+
+func TestReadCSVInvalid(t *testing.T) {
+	tests := []struct {
+		name, s, err string
+	}{
+		{"ragged", "1,2\n3,4\n5\n", "Line 3"},
+		{"non-numeric", "1,2\n3,x\n", "Line 2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := matrix.ReadCSV(strings.NewReader(tt.s))
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("Got error %v, want error naming %s", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestRowReader(t *testing.T) {
+	rr, err := matrix.NewRowReader(strings.NewReader("1,2\n3,4\n5\n6,7\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range [][]float64{{1, 2}, {3, 4}} {
+		row, err := rr.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(row, want) {
+			t.Errorf("Got row %v, want %v", row, want)
+		}
+	}
+	if _, err := rr.Next(); err == nil || !strings.Contains(err.Error(), "Line 3") {
+		t.Errorf("Got error %v, want error naming line 3", err)
+	}
+	if row, err := rr.Next(); err != nil || !slices.Equal(row, []float64{6, 7}) {
+		t.Errorf("Got row %v (%v) after ragged row, want [6 7]", row, err)
+	}
+	if _, err := rr.Next(); err != io.EOF {
+		t.Errorf("Got error %v, want %v", err, io.EOF)
+	}
+	rr, _ = matrix.NewRowReader(strings.NewReader("1,x\n"))
+	if _, err := rr.Next(); err == nil {
+		t.Error("Expected error for non-numeric value")
+	}
+	if _, err := matrix.NewRowReader(nil); err == nil {
+		t.Error("Expected error for nil reader")
+	}
+}
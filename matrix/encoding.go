@@ -0,0 +1,154 @@
+package matrix
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// binaryMagic is a signature at the beginning of binary representation of the matrix
+var binaryMagic = [4]byte{'A', 'L', 'G', 'M'}
+
+// binaryVersion is a version of binary representation of the matrix
+const binaryVersion uint8 = 1
+
+// binaryChunk is a count of elements read at once from binary representation of the matrix
+const binaryChunk = 1 << 16
+
+// binaryHeader is a header of binary representation of the matrix
+type binaryHeader struct {
+	Magic   [4]byte
+	Version uint8
+	Rows    int32
+	Cols    int32
+}
+
+// encodedMatrix is a serializable representation of the matrix
+type encodedMatrix struct {
+	Rows int       `json:"rows"`
+	Cols int       `json:"cols"`
+	Data []float64 `json:"data"`
+}
+
+// MarshalJSON implements json.Marshaler interface
+func (m *Matrix) MarshalJSON() ([]byte, error) {
+	m.RLock()
+	defer m.RUnlock()
+	return json.Marshal(encodedMatrix{
+		Rows: m.rows,
+		Cols: m.cols,
+		Data: m.data,
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface
+func (m *Matrix) UnmarshalJSON(b []byte) error {
+	e := encodedMatrix{}
+	if err := json.Unmarshal(b, &e); err != nil {
+		return err
+	}
+	return m.load(e.Rows, e.Cols, e.Data)
+}
+
+// GobEncode implements gob.GobEncoder interface
+func (m *Matrix) GobEncode() ([]byte, error) {
+	m.RLock()
+	defer m.RUnlock()
+	b := &bytes.Buffer{}
+	err := gob.NewEncoder(b).Encode(encodedMatrix{
+		Rows: m.rows,
+		Cols: m.cols,
+		Data: m.data,
+	})
+	return b.Bytes(), err
+}
+
+// GobDecode implements gob.GobDecoder interface
+func (m *Matrix) GobDecode(b []byte) error {
+	e := encodedMatrix{}
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&e); err != nil {
+		return err
+	}
+	return m.load(e.Rows, e.Cols, e.Data)
+}
+
+// WriteBinary writes the matrix to w in little-endian binary representation
+// consisting of header with dimentions followed by elements in row-major order
+func (m *Matrix) WriteBinary(w io.Writer) error {
+	m.RLock()
+	defer m.RUnlock()
+	if m.rows > math.MaxInt32 || m.cols > math.MaxInt32 {
+		return fmt.Errorf("Dimentions %dx%d are too large for binary representation", m.rows, m.cols)
+	}
+	h := binaryHeader{
+		Magic:   binaryMagic,
+		Version: binaryVersion,
+		Rows:    int32(m.rows),
+		Cols:    int32(m.cols),
+	}
+	if err := binary.Write(w, binary.LittleEndian, h); err != nil {
+		return err
+	}
+	return binary.Write(w, binary.LittleEndian, m.data)
+}
+
+// ReadBinary returns pointer to the new matrix read from little-endian binary representation
+func ReadBinary(r io.Reader) (*Matrix, error) {
+	h := binaryHeader{}
+	if err := binary.Read(r, binary.LittleEndian, &h); err != nil {
+		return nil, err
+	}
+	if h.Magic != binaryMagic {
+		return nil, errors.New("Binary representation of the matrix has invalid signature")
+	}
+	if h.Version != binaryVersion {
+		return nil, fmt.Errorf("Version %d of binary representation of the matrix is not supported", h.Version)
+	}
+	rows, cols := int(h.Rows), int(h.Cols)
+	if rows < 0 || cols < 0 {
+		return nil, fmt.Errorf("Dimetions %dx%d must not being negative", rows, cols)
+	}
+	if cols != 0 && rows > math.MaxInt/cols {
+		return nil, fmt.Errorf("Dimentions %dx%d are too large", rows, cols)
+	}
+	// Elements are read in chunks so that corrupted header cannot cause allocation
+	// of more memory than the data actually present in r
+	n := rows * cols
+	data := make([]float64, 0, min(n, binaryChunk))
+	chunk := make([]float64, min(n, binaryChunk))
+	for len(data) < n {
+		c := chunk[:min(n-len(data), len(chunk))]
+		if err := binary.Read(r, binary.LittleEndian, c); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		data = append(data, c...)
+	}
+	return &Matrix{rows: rows, cols: cols, data: data}, nil
+}
+
+// load replaces dimentions and elements of the matrix after validation
+func (m *Matrix) load(rows, cols int, data []float64) error {
+	if rows < 0 || cols < 0 {
+		return fmt.Errorf("Dimetions %dx%d must not being negative", rows, cols)
+	}
+	if cols != 0 && rows > math.MaxInt/cols {
+		return fmt.Errorf("Dimentions %dx%d are too large", rows, cols)
+	}
+	if len(data) != rows*cols {
+		return fmt.Errorf("Count of elements %d is not equal to %dx%d", len(data), rows, cols)
+	}
+	d := make([]float64, rows*cols)
+	copy(d, data)
+	m.Lock()
+	m.rows, m.cols, m.data = rows, cols, d
+	m.Unlock()
+	return nil
+}
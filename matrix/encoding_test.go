@@ -0,0 +1,111 @@
+package matrix_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"testing"
+
+	"github.com/andreipimenov/algebra/matrix"
+	"github.com/andreipimenov/algebra/matrix/matrixtest"
+)
+
+func binaryHeader(magic string, version uint8, rows, cols int32) []byte {
+	b := &bytes.Buffer{}
+	b.WriteString(magic)
+	b.WriteByte(version)
+	binary.Write(b, binary.LittleEndian, rows)
+	binary.Write(b, binary.LittleEndian, cols)
+	return b.Bytes()
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	for _, m := range []*matrix.Matrix{
+		fromSlice(t, [][]float64{{1.5, -2, 3}, {4, 5e-300, 6e300}}),
+		newMatrix(t, 0, 0),
+		newMatrix(t, 300, 300),
+	} {
+		b := &bytes.Buffer{}
+		if err := m.WriteBinary(b); err != nil {
+			t.Fatal(err)
+		}
+		r, err := matrix.ReadBinary(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		matrixtest.AssertEqual(t, r, m, 0)
+	}
+}
+
+func TestReadBinaryCorrupted(t *testing.T) {
+	tests := []struct {
+		name string
+		b    []byte
+	}{
+		{"signature", binaryHeader("ALGX", 1, 1, 1)},
+		{"version", binaryHeader("ALGM", 2, 1, 1)},
+		{"negative", binaryHeader("ALGM", 1, -1, 1)},
+		{"short header", binaryHeader("ALGM", 1, 1, 1)[:7]},
+		{"huge", binaryHeader("ALGM", 1, 2000000000, 2000000000)},
+		{"truncated", append(binaryHeader("ALGM", 1, 2, 2), make([]byte, 8*3)...)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := matrix.ReadBinary(bytes.NewReader(tt.b)); err == nil {
+				t.Error("Expected error for corrupted binary representation")
+			}
+		})
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	for _, m := range []*matrix.Matrix{
+		fromSlice(t, [][]float64{{1.5, -2, 3}, {4, 5e-300, 6e300}}),
+		newMatrix(t, 0, 0),
+	} {
+		b, err := json.Marshal(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := &matrix.Matrix{}
+		if err := json.Unmarshal(b, r); err != nil {
+			t.Fatal(err)
+		}
+		if !r.Equals(m) {
+			t.Errorf("Got %v after round trip, want %v", r, m)
+		}
+	}
+}
+
+func TestUnmarshalJSONInvalid(t *testing.T) {
+	for _, s := range []string{
+		`{"rows":2,"cols":2,"data":[1,2,3]}`,
+		`{"rows":-1,"cols":0,"data":[]}`,
+		`{"rows":4294967296,"cols":4294967296,"data":[]}`,
+		`{"rows":1,"cols":1,"data":["a"]}`,
+	} {
+		if err := json.Unmarshal([]byte(s), &matrix.Matrix{}); err == nil {
+			t.Errorf("Expected error for %s", s)
+		}
+	}
+}
+
+func TestGobRoundTrip(t *testing.T) {
+	for _, m := range []*matrix.Matrix{
+		fromSlice(t, [][]float64{{1.5, -2, 3}, {4, 5e-300, 6e300}}),
+		newMatrix(t, 0, 0),
+	} {
+		b := &bytes.Buffer{}
+		if err := gob.NewEncoder(b).Encode(m); err != nil {
+			t.Fatal(err)
+		}
+		r := &matrix.Matrix{}
+		if err := gob.NewDecoder(b).Decode(r); err != nil {
+			t.Fatal(err)
+		}
+		if !r.Equals(m) {
+			t.Errorf("Got %v after round trip, want %v", r, m)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+// Package generic implements operations with matrices of any floating-point type in golang
+package generic
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+)
+
+// Float is a constraint for floating-point types of matrix elements
+type Float interface {
+	~float32 | ~float64
+}
+
+// Matrix is a basic type for 2-dimentional matrices
+// which consists of rows, columns and slice of elements of type T
+type Matrix[T Float] struct {
+	rows int
+	cols int
+	data []T
+	sync.RWMutex
+}
+
+// New returns pointer to the new empty matrix with given dimentions
+func New[T Float](rows, cols int) (*Matrix[T], error) {
+	if rows < 0 || cols < 0 {
+		return nil, fmt.Errorf("Dimetions %dx%d must not being negative", rows, cols)
+	}
+	return &Matrix[T]{
+		rows: rows,
+		cols: cols,
+		data: make([]T, rows*cols),
+	}, nil
+}
+
+// String returns string representation of the matrix
+func (m *Matrix[T]) String() string {
+	b := &bytes.Buffer{}
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			fmt.Fprintf(b, "%-15.3f", m.get(i, j))
+		}
+		fmt.Fprintf(b, "\n")
+	}
+	return b.String()
+}
+
+// Dimentions returns count of rows and columns of the matrix
+func (m *Matrix[T]) Dimentions() (int, int) {
+	return m.rows, m.cols
+}
+
+// Clone returns new cloned matrix
+func (m *Matrix[T]) Clone() *Matrix[T] {
+	c := &Matrix[T]{
+		rows: m.rows,
+		cols: m.cols,
+		data: make([]T, m.rows*m.cols),
+	}
+	m.RLock()
+	copy(c.data, m.data)
+	m.RUnlock()
+	return c
+}
+
+func (m *Matrix[T]) checkRange(i, j int) error {
+	if i < 0 || j < 0 {
+		return fmt.Errorf("Position (%d, %d) must not being negative", i, j)
+	}
+	if i >= m.rows || j >= m.cols {
+		return fmt.Errorf("Position (%d, %d) is out of the range (0:%d, 0:%d)", i, j, m.rows-1, m.cols-1)
+	}
+	return nil
+}
+
+func (m *Matrix[T]) checkEqualDimentions(x *Matrix[T]) error {
+	if m.rows != x.rows || m.cols != x.cols {
+		return fmt.Errorf("Dimentions of two matrices %dx%d and %dx%d are not equal", m.rows, m.cols, x.rows, x.cols)
+	}
+	return nil
+}
+
+func (m *Matrix[T]) get(i, j int) T {
+	m.RLock()
+	defer m.RUnlock()
+	return m.data[m.cols*i+j]
+}
+
+func (m *Matrix[T]) set(i, j int, v T) {
+	m.Lock()
+	m.data[m.cols*i+j] = v
+	m.Unlock()
+}
+
+// Get returns the value of (i, j)
+func (m *Matrix[T]) Get(i, j int) (T, error) {
+	if err := m.checkRange(i, j); err != nil {
+		return 0, err
+	}
+	return m.get(i, j), nil
+}
+
+// Set sets the value at (i, j)
+func (m *Matrix[T]) Set(i, j int, v T) error {
+	if err := m.checkRange(i, j); err != nil {
+		return err
+	}
+	m.set(i, j, v)
+	return nil
+}
+
+// Each applies function to every element in the matrix
+func (m *Matrix[T]) Each(f func(i, j int, v T) T) {
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			m.set(i, j, f(i, j, m.get(i, j)))
+		}
+	}
+}
+
+// T returns new transposed matrix
+func (m *Matrix[T]) T() *Matrix[T] {
+	t := &Matrix[T]{
+		rows: m.cols,
+		cols: m.rows,
+		data: make([]T, m.rows*m.cols),
+	}
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			t.set(j, i, m.get(i, j))
+		}
+	}
+	return t
+}
+
+// Add adds the matrix
+func (m *Matrix[T]) Add(x *Matrix[T]) error {
+	if err := m.checkEqualDimentions(x); err != nil {
+		return err
+	}
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			m.set(i, j, m.get(i, j)+x.get(i, j))
+		}
+	}
+	return nil
+}
+
+// Sub subtracts the matrix
+func (m *Matrix[T]) Sub(x *Matrix[T]) error {
+	if err := m.checkEqualDimentions(x); err != nil {
+		return err
+	}
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			m.set(i, j, m.get(i, j)-x.get(i, j))
+		}
+	}
+	return nil
+}
+
+// Addn adds number to every element in the matrix
+func (m *Matrix[T]) Addn(n T) {
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			m.set(i, j, m.get(i, j)+n)
+		}
+	}
+}
+
+// Scale scales matrix with given factor
+func (m *Matrix[T]) Scale(n T) {
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			m.set(i, j, m.get(i, j)*n)
+		}
+	}
+}
+
+// Dot returns sum of products of corresponding elements of matrices
+//
+// Deprecated: Dot is not a matrix product, use FrobeniusInner for the same result
+func (m *Matrix[T]) Dot(x *Matrix[T]) (T, error) {
+	return m.FrobeniusInner(x)
+}
+
+// FrobeniusInner returns Frobenius inner product of matrices
+// which is the sum of products of corresponding elements
+func (m *Matrix[T]) FrobeniusInner(x *Matrix[T]) (T, error) {
+	if err := m.checkEqualDimentions(x); err != nil {
+		return 0, err
+	}
+	r := T(0)
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			r += m.get(i, j) * x.get(i, j)
+		}
+	}
+	return r, nil
+}
@@ -0,0 +1,87 @@
+package generic_test
+
+import (
+	"testing"
+
+	"github.com/andreipimenov/algebra/matrix/generic"
+)
+
+func fill[T generic.Float](t *testing.T, rows, cols int, values ...T) *generic.Matrix[T] {
+	t.Helper()
+	m, err := generic.New[T](rows, cols)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range values {
+		if err := m.Set(k/cols, k%cols, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return m
+}
+
+func assertElements[T generic.Float](t *testing.T, m *generic.Matrix[T], rows, cols int, want ...T) {
+	t.Helper()
+	if r, c := m.Dimentions(); r != rows || c != cols {
+		t.Fatalf("Got dimentions %dx%d, want %dx%d", r, c, rows, cols)
+	}
+	for k, w := range want {
+		if v, _ := m.Get(k/cols, k%cols); v != w {
+			t.Errorf("Got %v at (%d, %d), want %v", v, k/cols, k%cols, w)
+		}
+	}
+}
+
+func testMatrix[T generic.Float](t *testing.T) {
+	if _, err := generic.New[T](-1, 2); err == nil {
+		t.Error("Expected error for negative dimentions")
+	}
+	m := fill[T](t, 2, 3, 1, 2, 3, 4, 5, 6)
+	if _, err := m.Get(2, 0); err == nil {
+		t.Error("Expected error for out of range position")
+	}
+	assertElements(t, m.T(), 3, 2, 1, 4, 2, 5, 3, 6)
+
+	c := m.Clone()
+	c.Set(0, 0, 10)
+	assertElements(t, m, 2, 3, 1, 2, 3, 4, 5, 6)
+
+	if err := c.Add(m); err != nil {
+		t.Fatal(err)
+	}
+	assertElements(t, c, 2, 3, 11, 4, 6, 8, 10, 12)
+	if err := c.Sub(m); err != nil {
+		t.Fatal(err)
+	}
+	assertElements(t, c, 2, 3, 10, 2, 3, 4, 5, 6)
+	if err := c.Add(m.T()); err == nil {
+		t.Error("Expected error for mismatched dimentions")
+	}
+
+	c.Scale(2)
+	c.Addn(1)
+	assertElements(t, c, 2, 3, 21, 5, 7, 9, 11, 13)
+	c.Each(func(i, j int, v T) T { return T(i*10 + j) })
+	assertElements(t, c, 2, 3, 0, 1, 2, 10, 11, 12)
+
+	if p, err := m.FrobeniusInner(m); err != nil || p != 91 {
+		t.Errorf("Got Frobenius inner product %v (%v), want 91", p, err)
+	}
+	if d, _ := m.Dot(m); d != 91 {
+		t.Errorf("Got dot product %v, want 91", d)
+	}
+	if _, err := m.FrobeniusInner(m.T()); err == nil {
+		t.Error("Expected error for mismatched dimentions")
+	}
+	if m.String() == "" {
+		t.Error("Got empty string representation")
+	}
+}
+
+func TestFloat32(t *testing.T) {
+	testMatrix[float32](t)
+}
+
+func TestFloat64(t *testing.T) {
+	testMatrix[float64](t)
+}
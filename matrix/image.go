@@ -0,0 +1,44 @@
+package matrix
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+)
+
+// FromGray returns pointer to the new matrix filled with pixels of the grayscale image
+// scaled to [0, 1], rows of the matrix correspond to the height of the image
+func FromGray(img *image.Gray) *Matrix {
+	b := img.Bounds()
+	m := &Matrix{
+		rows: b.Dy(),
+		cols: b.Dx(),
+		data: make([]float64, b.Dy()*b.Dx()),
+	}
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			m.data[m.cols*i+j] = float64(img.GrayAt(b.Min.X+j, b.Min.Y+i).Y) / 255
+		}
+	}
+	return m
+}
+
+// ToGray returns new grayscale image with pixels taken from elements of the matrix
+// clamped to [0, 1] and scaled to [0, 255]
+func (m *Matrix) ToGray() (*image.Gray, error) {
+	img := image.NewGray(image.Rect(0, 0, m.cols, m.rows))
+	m.RLock()
+	defer m.RUnlock()
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			v := m.data[m.cols*i+j]
+			if math.IsNaN(v) {
+				return nil, fmt.Errorf("Position (%d, %d) is NaN", i, j)
+			}
+			v = math.Max(0, math.Min(1, v))
+			img.SetGray(j, i, color.Gray{Y: uint8(math.Round(v * 255))})
+		}
+	}
+	return img, nil
+}
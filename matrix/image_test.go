@@ -0,0 +1,49 @@
+package matrix_test
+
+import (
+	"image"
+	"math"
+	"testing"
+
+	"github.com/andreipimenov/algebra/matrix"
+	"github.com/andreipimenov/algebra/matrix/matrixtest"
+)
+
+func TestGrayRoundTrip(t *testing.T) {
+	img := image.NewGray(image.Rect(2, 3, 5, 5))
+	for k := range img.Pix {
+		img.Pix[k] = uint8(k * 37)
+	}
+	m := matrix.FromGray(img)
+	if rows, cols := m.Dimentions(); rows != 2 || cols != 3 {
+		t.Fatalf("Got dimentions %dx%d, want 2x3", rows, cols)
+	}
+	if v, _ := m.Get(1, 2); math.Abs(v-float64(img.GrayAt(4, 4).Y)/255) > 1e-15 {
+		t.Errorf("Got %g at (1, 2), want %g", v, float64(img.GrayAt(4, 4).Y)/255)
+	}
+	r, err := m.ToGray()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 3; j++ {
+			if got, want := r.GrayAt(j, i).Y, img.GrayAt(2+j, 3+i).Y; got != want {
+				t.Errorf("Got pixel %d at (%d, %d), want %d", got, j, i, want)
+			}
+		}
+	}
+}
+
+func TestToGray(t *testing.T) {
+	r, err := fromSlice(t, [][]float64{{-1, 0.5, 2}}).ToGray()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := []uint8{r.GrayAt(0, 0).Y, r.GrayAt(1, 0).Y, r.GrayAt(2, 0).Y}; got[0] != 0 || got[1] != 128 || got[2] != 255 {
+		t.Errorf("Got pixels %v, want [0 128 255]", got)
+	}
+	if _, err := fromSlice(t, [][]float64{{math.NaN()}}).ToGray(); err == nil {
+		t.Error("Expected error for NaN")
+	}
+	matrixtest.AssertEqual(t, matrix.FromGray(image.NewGray(image.Rect(0, 0, 0, 0))), newMatrix(t, 0, 0), 0)
+}
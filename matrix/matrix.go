@@ -1,12 +1,44 @@
 // Package matrix implements operations with matrices in golang
+//
+// Methods such as Add, Sub and Scale modify the receiver in place,
+// while their counterparts Plus, Minus and Scaled return new matrix leaving the receiver untouched
 package matrix
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"iter"
+	"math"
+	"math/rand"
+	"runtime"
+	"sort"
+	"strconv"
 	"sync"
 )
 
+// ErrSingular is returned when operation requires non-singular matrix
+var ErrSingular = errors.New("Matrix is singular")
+
+// ErrNotSymmetric is returned when operation requires symmetric matrix
+var ErrNotSymmetric = errors.New("Matrix is not symmetric")
+
+// ErrNotPositiveDefinite is returned when operation requires positive definite matrix
+var ErrNotPositiveDefinite = errors.New("Matrix is not positive definite")
+
+// ErrEmpty is returned when operation requires non-empty matrix
+var ErrEmpty = errors.New("Matrix is empty")
+
+// tileSize is a default size of tiles for blocked multiplication
+const tileSize = 64
+
+// blockedMulSize is a minimal dimention of matrices multiplied with blocked algorithm
+const blockedMulSize = 128
+
+// eps is a tolerance under which pivot is considered to be zero
+const eps = 1e-12
+
 // Matrix is a basic type for 2-dimentional matrices
 // which consists of rows, columns and slice of elements
 type Matrix struct {
@@ -28,15 +60,174 @@ func New(rows, cols int) (*Matrix, error) {
 	}, nil
 }
 
+// Identity returns pointer to the new identity matrix with given dimention
+func Identity(n int) (*Matrix, error) {
+	m, err := New(n, n)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < n; i++ {
+		m.set(i, i, 1)
+	}
+	return m, nil
+}
+
+// FromSlice returns pointer to the new matrix filled with values of 2-dimentional slice
+func FromSlice(data [][]float64) (*Matrix, error) {
+	rows, cols := len(data), 0
+	if rows > 0 {
+		cols = len(data[0])
+	}
+	m := &Matrix{
+		rows: rows,
+		cols: cols,
+		data: make([]float64, rows*cols),
+	}
+	for i, row := range data {
+		if len(row) != cols {
+			return nil, fmt.Errorf("Row %d has %d elements, expected %d", i, len(row), cols)
+		}
+		copy(m.data[cols*i:], row)
+	}
+	return m, nil
+}
+
+// Diagonal returns pointer to the new square matrix with given values on the main diagonal
+func Diagonal(values []float64) *Matrix {
+	n := len(values)
+	m := &Matrix{
+		rows: n,
+		cols: n,
+		data: make([]float64, n*n),
+	}
+	for i, v := range values {
+		m.data[n*i+i] = v
+	}
+	return m
+}
+
+// Random returns pointer to the new matrix filled with random values in [0, 1)
+// taken from given source or from the global one if source is nil
+func Random(rows, cols int, src *rand.Rand) (*Matrix, error) {
+	return RandomRange(rows, cols, 0, 1, src)
+}
+
+// RandomRange returns pointer to the new matrix filled with random values in [lo, hi)
+// taken from given source or from the global one if source is nil
+func RandomRange(rows, cols int, lo, hi float64, src *rand.Rand) (*Matrix, error) {
+	m, err := New(rows, cols)
+	if err != nil {
+		return nil, err
+	}
+	f := rand.Float64
+	if src != nil {
+		f = src.Float64
+	}
+	for k := range m.data {
+		m.data[k] = lo + (hi-lo)*f()
+	}
+	return m, nil
+}
+
 // String returns string representation of the matrix
 func (m *Matrix) String() string {
+	return m.Format(3, 15)
+}
+
+// Format returns string representation of the matrix with given decimal places
+// and column width, non-positive width is sized to fit the longest value
+func (m *Matrix) Format(precision, width int) string {
+	return format(m.rows, m.cols, m.get, precision, width)
+}
+
+// format returns string representation of rows×cols elements returned by get
+// with given decimal places and column width, non-positive width is sized to fit the longest value
+func format(rows, cols int, get func(i, j int) float64, precision, width int) string {
+	if width <= 0 {
+		for i := 0; i < rows; i++ {
+			for j := 0; j < cols; j++ {
+				width = max(width, len(fmt.Sprintf("%.*f", precision, get(i, j)))+1)
+			}
+		}
+	}
 	b := &bytes.Buffer{}
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			fmt.Fprintf(b, "%-*.*f", width, precision, get(i, j))
+		}
+		fmt.Fprintf(b, "\n")
+	}
+	return b.String()
+}
+
+// StringLabeled returns string representation of the matrix with column headers
+// and row labels, nil labels are replaced with numeric indices
+func (m *Matrix) StringLabeled(rowLabels, colLabels []string) (string, error) {
+	if rowLabels == nil {
+		rowLabels = make([]string, m.rows)
+		for i := range rowLabels {
+			rowLabels[i] = strconv.Itoa(i)
+		}
+	}
+	if colLabels == nil {
+		colLabels = make([]string, m.cols)
+		for j := range colLabels {
+			colLabels[j] = strconv.Itoa(j)
+		}
+	}
+	if len(rowLabels) != m.rows || len(colLabels) != m.cols {
+		return "", fmt.Errorf("Count of row labels %d and column labels %d does not match dimentions %dx%d", len(rowLabels), len(colLabels), m.rows, m.cols)
+	}
+	cells := make([]string, m.rows*m.cols)
+	lw := 0
+	for _, l := range rowLabels {
+		lw = max(lw, len(l))
+	}
+	cw := make([]int, m.cols)
+	for j, l := range colLabels {
+		cw[j] = len(l)
+	}
 	for i := 0; i < m.rows; i++ {
 		for j := 0; j < m.cols; j++ {
-			fmt.Fprintf(b, "%-15.3f", m.get(i, j))
+			cells[m.cols*i+j] = fmt.Sprintf("%.3f", m.get(i, j))
+			cw[j] = max(cw[j], len(cells[m.cols*i+j]))
+		}
+	}
+	b := &bytes.Buffer{}
+	fmt.Fprintf(b, "%-*s", lw, "")
+	for j, l := range colLabels {
+		fmt.Fprintf(b, " %-*s", cw[j], l)
+	}
+	fmt.Fprintf(b, "\n")
+	for i, l := range rowLabels {
+		fmt.Fprintf(b, "%-*s", lw, l)
+		for j := 0; j < m.cols; j++ {
+			fmt.Fprintf(b, " %-*s", cw[j], cells[m.cols*i+j])
 		}
 		fmt.Fprintf(b, "\n")
 	}
+	return b.String(), nil
+}
+
+// LaTeX returns LaTeX representation of the matrix with 3 decimal places
+func (m *Matrix) LaTeX() string {
+	return m.LaTeXPrecision(3)
+}
+
+// LaTeXPrecision returns LaTeX representation of the matrix with given decimal places
+func (m *Matrix) LaTeXPrecision(precision int) string {
+	b := &bytes.Buffer{}
+	fmt.Fprintf(b, "\\begin{pmatrix}\n")
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			if j > 0 {
+				fmt.Fprintf(b, " & ")
+			}
+			fmt.Fprintf(b, "%.*f", precision, m.get(i, j))
+		}
+		fmt.Fprintf(b, " \\\\\n")
+	}
+	fmt.Fprintf(b, "\\end{pmatrix}")
 	return b.String()
 }
 
@@ -52,10 +243,57 @@ func (m *Matrix) Clone() *Matrix {
 		cols: m.cols,
 		data: make([]float64, m.rows*m.cols),
 	}
+	m.RLock()
 	copy(c.data, m.data)
+	m.RUnlock()
 	return c
 }
 
+// CopyInto copies elements of the matrix into given matrix of the same dimentions
+func (m *Matrix) CopyInto(dst *Matrix) error {
+	if err := m.checkEqualDimentions(dst); err != nil {
+		return err
+	}
+	if dst == m {
+		return nil
+	}
+	// The matrix is not kept locked while dst is written,
+	// otherwise copies in opposite directions may deadlock
+	data := m.Clone().data
+	dst.Lock()
+	defer dst.Unlock()
+	copy(dst.data, data)
+	return nil
+}
+
+// ToSlice returns new 2-dimentional slice filled with values of the matrix
+func (m *Matrix) ToSlice() [][]float64 {
+	m.RLock()
+	defer m.RUnlock()
+	s := make([][]float64, m.rows)
+	for i := range s {
+		s[i] = make([]float64, m.cols)
+		copy(s[i], m.data[m.cols*i:m.cols*(i+1)])
+	}
+	return s
+}
+
+// View returns snapshot of the matrix taken under single read lock.
+// It is intended for read-heavy concurrent scenarios when elements are read repeatedly
+// without locking the matrix for every element. Unlike ToSlice all rows of the snapshot
+// share single backing slice
+func (m *Matrix) View() [][]float64 {
+	m.RLock()
+	d := make([]float64, len(m.data))
+	copy(d, m.data)
+	m.RUnlock()
+	s := make([][]float64, m.rows)
+	for i := range s {
+		s[i] = d[m.cols*i : m.cols*(i+1) : m.cols*(i+1)]
+	}
+	return s
+}
+
 func (m *Matrix) checkRange(i, j int) error {
 	if i < 0 || j < 0 {
 		return fmt.Errorf("Position (%d, %d) must not being negative", i, j)
@@ -66,6 +304,26 @@ func (m *Matrix) checkRange(i, j int) error {
 	return nil
 }
 
+func (m *Matrix) checkRow(i int) error {
+	if i < 0 {
+		return fmt.Errorf("Row %d must not being negative", i)
+	}
+	if i >= m.rows {
+		return fmt.Errorf("Row %d is out of the range (0:%d)", i, m.rows-1)
+	}
+	return nil
+}
+
+func (m *Matrix) checkCol(j int) error {
+	if j < 0 {
+		return fmt.Errorf("Column %d must not being negative", j)
+	}
+	if j >= m.cols {
+		return fmt.Errorf("Column %d is out of the range (0:%d)", j, m.cols-1)
+	}
+	return nil
+}
+
 func (m *Matrix) checkEqualDimentions(x *Matrix) error {
 	if m.rows != x.rows || m.cols != x.cols {
 		return fmt.Errorf("Dimentions of two matrices %dx%d and %dx%d are not equal", m.rows, m.cols, x.rows, x.cols)
@@ -73,6 +331,27 @@ func (m *Matrix) checkEqualDimentions(x *Matrix) error {
 	return nil
 }
 
+func (m *Matrix) checkSquare() error {
+	if m.rows != m.cols {
+		return fmt.Errorf("Matrix %dx%d is not square", m.rows, m.cols)
+	}
+	return nil
+}
+
+func (m *Matrix) checkVector() error {
+	if m.rows != 1 && m.cols != 1 {
+		return fmt.Errorf("Matrix %dx%d is not a vector", m.rows, m.cols)
+	}
+	return nil
+}
+
+func (m *Matrix) checkMulDimentions(x *Matrix) error {
+	if m.cols != x.rows {
+		return fmt.Errorf("Dimentions of two matrices %dx%d and %dx%d are not compatible for multiplication", m.rows, m.cols, x.rows, x.cols)
+	}
+	return nil
+}
+
 func (m *Matrix) get(i, j int) float64 {
 	m.RLock()
 	defer m.RUnlock()
@@ -102,6 +381,46 @@ func (m *Matrix) Set(i, j int, v float64) error {
 	return nil
 }
 
+// MustGet returns the value of (i, j) and panics if position is out of the range.
+// It is intended for performance-sensitive code where indices are known to be valid
+func (m *Matrix) MustGet(i, j int) float64 {
+	if err := m.checkRange(i, j); err != nil {
+		panic(err)
+	}
+	return m.get(i, j)
+}
+
+// MustSet sets the value at (i, j) and panics if position is out of the range.
+// It is intended for performance-sensitive code where indices are known to be valid
+func (m *Matrix) MustSet(i, j int, v float64) {
+	if err := m.checkRange(i, j); err != nil {
+		panic(err)
+	}
+	m.set(i, j, v)
+}
+
+// Entry is a value at position (I, J) of the matrix
+type Entry struct {
+	I, J int
+	V    float64
+}
+
+// SetBatch sets values of all entries under single lock,
+// nothing is set if any entry is out of the range
+func (m *Matrix) SetBatch(entries []Entry) error {
+	for k, e := range entries {
+		if err := m.checkRange(e.I, e.J); err != nil {
+			return fmt.Errorf("Entry %d: %v", k, err)
+		}
+	}
+	m.Lock()
+	defer m.Unlock()
+	for _, e := range entries {
+		m.data[m.cols*e.I+e.J] = e.V
+	}
+	return nil
+}
+
 // Each applies function to every element in the matrix
 func (m *Matrix) Each(f func(i, j int, v float64) float64) {
 	for i := 0; i < m.rows; i++ {
@@ -111,6 +430,88 @@ func (m *Matrix) Each(f func(i, j int, v float64) float64) {
 	}
 }
 
+// All returns iterator over positions and values of all elements in row-major order.
+// The matrix is locked for reading during the whole iteration
+// so the loop body must not modify it
+func (m *Matrix) All() iter.Seq2[[2]int, float64] {
+	return func(yield func([2]int, float64) bool) {
+		m.RLock()
+		defer m.RUnlock()
+		for i := 0; i < m.rows; i++ {
+			for j := 0; j < m.cols; j++ {
+				if !yield([2]int{i, j}, m.data[m.cols*i+j]) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// ParallelEach applies function to every element in the matrix
+// dividing rows between runtime.NumCPU() goroutines.
+// Function must be safe to call concurrently and must not access the matrix
+// because it is locked for writing during the whole operation
+func (m *Matrix) ParallelEach(f func(i, j int, v float64) float64) {
+	m.Lock()
+	defer m.Unlock()
+	n := runtime.NumCPU()
+	if n > m.rows {
+		n = m.rows
+	}
+	wg := sync.WaitGroup{}
+	for w := 0; w < n; w++ {
+		wg.Add(1)
+		go func(r0, r1 int) {
+			defer wg.Done()
+			for i := r0; i < r1; i++ {
+				for j := 0; j < m.cols; j++ {
+					m.data[m.cols*i+j] = f(i, j, m.data[m.cols*i+j])
+				}
+			}
+		}(m.rows*w/n, m.rows*(w+1)/n)
+	}
+	wg.Wait()
+}
+
+// EachCtx applies function to every element in the matrix
+// dividing rows between runtime.NumCPU() goroutines.
+// It stops when context is cancelled returning ctx.Err() or when function fails
+// returning the first error, elements processed before that remain changed.
+// Function must be safe to call concurrently
+func (m *Matrix) EachCtx(ctx context.Context, f func(i, j int, v float64) (float64, error)) error {
+	c, cancel := context.WithCancel(ctx)
+	defer cancel()
+	n := min(runtime.NumCPU(), m.rows)
+	once := sync.Once{}
+	var ferr error
+	wg := sync.WaitGroup{}
+	for w := 0; w < n; w++ {
+		wg.Add(1)
+		go func(r0, r1 int) {
+			defer wg.Done()
+			for i := r0; i < r1; i++ {
+				for j := 0; j < m.cols; j++ {
+					if c.Err() != nil {
+						return
+					}
+					v, err := f(i, j, m.get(i, j))
+					if err != nil {
+						once.Do(func() { ferr = err })
+						cancel()
+						return
+					}
+					m.set(i, j, v)
+				}
+			}
+		}(m.rows*w/n, m.rows*(w+1)/n)
+	}
+	wg.Wait()
+	if ferr != nil {
+		return ferr
+	}
+	return ctx.Err()
+}
+
 // T returns new transposed matrix
 func (m *Matrix) T() *Matrix {
 	t := &Matrix{
@@ -126,6 +527,22 @@ func (m *Matrix) T() *Matrix {
 	return t
 }
 
+// TransposeInPlace transposes the square matrix without allocation
+func (m *Matrix) TransposeInPlace() error {
+	if err := m.checkSquare(); err != nil {
+		return err
+	}
+	m.Lock()
+	defer m.Unlock()
+	n := m.rows
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			m.data[n*i+j], m.data[n*j+i] = m.data[n*j+i], m.data[n*i+j]
+		}
+	}
+	return nil
+}
+
 // Add adds the matrix
 func (m *Matrix) Add(x *Matrix) error {
 	if err := m.checkEqualDimentions(x); err != nil {
@@ -152,6 +569,24 @@ func (m *Matrix) Sub(x *Matrix) error {
 	return nil
 }
 
+// Plus returns new matrix which is the sum of the matrix and given one
+func (m *Matrix) Plus(x *Matrix) (*Matrix, error) {
+	r := m.Clone()
+	if err := r.Add(x); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Minus returns new matrix which is the difference of the matrix and given one
+func (m *Matrix) Minus(x *Matrix) (*Matrix, error) {
+	r := m.Clone()
+	if err := r.Sub(x); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
 // Addn adds number to every element in the matrix
 func (m *Matrix) Addn(n float64) {
 	for i := 0; i < m.rows; i++ {
@@ -170,8 +605,24 @@ func (m *Matrix) Scale(n float64) {
 	}
 }
 
-// Dot returns dot product of matrices
+// Scaled returns new matrix which is the matrix multiplied by number
+func (m *Matrix) Scaled(n float64) *Matrix {
+	r := m.Clone()
+	r.Scale(n)
+	return r
+}
+
+// Dot returns sum of products of corresponding elements of matrices
+//
+// Deprecated: Dot is not a matrix product, use FrobeniusInner for the same result
+// or Mul for matrix multiplication
 func (m *Matrix) Dot(x *Matrix) (float64, error) {
+	return m.FrobeniusInner(x)
+}
+
+// FrobeniusInner returns Frobenius inner product of matrices
+// which is the sum of products of corresponding elements
+func (m *Matrix) FrobeniusInner(x *Matrix) (float64, error) {
 	if err := m.checkEqualDimentions(x); err != nil {
 		return 0, err
 	}
@@ -183,3 +634,2070 @@ func (m *Matrix) Dot(x *Matrix) (float64, error) {
 	}
 	return r, nil
 }
+
+// Mul returns new matrix which is the product of the matrix and given one.
+// Large matrices are multiplied with blocked algorithm for better cache locality
+func (m *Matrix) Mul(x *Matrix) (*Matrix, error) {
+	if err := m.checkMulDimentions(x); err != nil {
+		return nil, err
+	}
+	if m.rows >= blockedMulSize && m.cols >= blockedMulSize && x.cols >= blockedMulSize {
+		return m.MulTiled(x, tileSize)
+	}
+	return m.mulNaive(x), nil
+}
+
+// mulNaive returns new matrix which is the product of the matrix and given one
+// calculated row by column, dimentions must be compatible
+func (m *Matrix) mulNaive(x *Matrix) *Matrix {
+	r := &Matrix{
+		rows: m.rows,
+		cols: x.cols,
+		data: make([]float64, m.rows*x.cols),
+	}
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < x.cols; j++ {
+			v := float64(0)
+			for k := 0; k < m.cols; k++ {
+				v += m.get(i, k) * x.get(k, j)
+			}
+			r.set(i, j, v)
+		}
+	}
+	return r
+}
+
+// MulTiled returns new matrix which is the product of the matrix and given one
+// calculated by square tiles of given size
+func (m *Matrix) MulTiled(x *Matrix, tile int) (*Matrix, error) {
+	if err := m.checkMulDimentions(x); err != nil {
+		return nil, err
+	}
+	if tile < 1 {
+		return nil, fmt.Errorf("Tile size %d must be positive", tile)
+	}
+	// The operand is copied before locking the matrix,
+	// so concurrent multiplications in opposite directions do not deadlock
+	b := x.Clone().data
+	m.RLock()
+	defer m.RUnlock()
+	n, p := m.cols, x.cols
+	r := &Matrix{
+		rows: m.rows,
+		cols: p,
+		data: make([]float64, m.rows*p),
+	}
+	for i0 := 0; i0 < m.rows; i0 += tile {
+		i1 := min(i0+tile, m.rows)
+		for k0 := 0; k0 < n; k0 += tile {
+			k1 := min(k0+tile, n)
+			for j0 := 0; j0 < p; j0 += tile {
+				j1 := min(j0+tile, p)
+				for i := i0; i < i1; i++ {
+					for k := k0; k < k1; k++ {
+						a := m.data[n*i+k]
+						for j := j0; j < j1; j++ {
+							r.data[p*i+j] += a * b[p*k+j]
+						}
+					}
+				}
+			}
+		}
+	}
+	return r, nil
+}
+
+// MulParallel returns new matrix which is the product of the matrix and given one
+// dividing rows of the result between runtime.NumCPU() goroutines
+func (m *Matrix) MulParallel(x *Matrix) (*Matrix, error) {
+	if err := m.checkMulDimentions(x); err != nil {
+		return nil, err
+	}
+	// The operand is copied before locking the matrix as in MulTiled
+	b := x.Clone().data
+	m.RLock()
+	defer m.RUnlock()
+	n, p := m.cols, x.cols
+	r := &Matrix{
+		rows: m.rows,
+		cols: p,
+		data: make([]float64, m.rows*p),
+	}
+	w := min(runtime.NumCPU(), m.rows)
+	wg := sync.WaitGroup{}
+	for c := 0; c < w; c++ {
+		wg.Add(1)
+		go func(i0, i1 int) {
+			defer wg.Done()
+			for i := i0; i < i1; i++ {
+				for k := 0; k < n; k++ {
+					a := m.data[n*i+k]
+					for j := 0; j < p; j++ {
+						r.data[p*i+j] += a * b[p*k+j]
+					}
+				}
+			}
+		}(m.rows*c/w, m.rows*(c+1)/w)
+	}
+	wg.Wait()
+	return r, nil
+}
+
+// Determinant returns determinant of the square matrix
+// calculated with closed-form formula for matrices up to 3x3
+// and with LU decomposition with partial pivoting for larger ones
+func (m *Matrix) Determinant() (float64, error) {
+	if err := m.checkSquare(); err != nil {
+		return 0, err
+	}
+	n := m.rows
+	a := m.Clone().data
+	switch n {
+	case 1:
+		return a[0], nil
+	case 2:
+		return a[0]*a[3] - a[1]*a[2], nil
+	case 3:
+		return a[0]*(a[4]*a[8]-a[5]*a[7]) -
+			a[1]*(a[3]*a[8]-a[5]*a[6]) +
+			a[2]*(a[3]*a[7]-a[4]*a[6]), nil
+	}
+	_, d, err := decomposeLU(a, n, 0)
+	if err != nil {
+		return 0, nil
+	}
+	for k := 0; k < n; k++ {
+		d *= a[n*k+k]
+	}
+	return d, nil
+}
+
+// LU returns lower unit triangular matrix l, upper triangular matrix u
+// and row permutation perm such that rows of the matrix taken in order of perm equal l*u
+func (m *Matrix) LU() (l, u *Matrix, perm []int, err error) {
+	if err := m.checkSquare(); err != nil {
+		return nil, nil, nil, err
+	}
+	n := m.rows
+	a := m.Clone().data
+	perm, _, err = decomposeLU(a, n, m.pivotTol())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	l, _ = Identity(n)
+	u, _ = New(n, n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if j < i {
+				l.data[n*i+j] = a[n*i+j]
+			} else {
+				u.data[n*i+j] = a[n*i+j]
+			}
+		}
+	}
+	return l, u, perm, nil
+}
+
+// Solve returns new matrix x which is the solution of linear system m*x = b
+// where every column of b is a separate right-hand side
+func (m *Matrix) Solve(b *Matrix) (*Matrix, error) {
+	if err := m.checkSquare(); err != nil {
+		return nil, err
+	}
+	if m.rows != b.rows {
+		return nil, fmt.Errorf("Dimentions of two matrices %dx%d and %dx%d are not compatible for solving", m.rows, m.cols, b.rows, b.cols)
+	}
+	n, k := m.rows, b.cols
+	a := m.Clone().data
+	perm, _, err := decomposeLU(a, n, m.pivotTol())
+	if err != nil {
+		return nil, err
+	}
+	x := &Matrix{
+		rows: n,
+		cols: k,
+		data: make([]float64, n*k),
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j < k; j++ {
+			x.data[k*i+j] = b.get(perm[i], j)
+		}
+	}
+	for j := 0; j < k; j++ {
+		for i := 0; i < n; i++ {
+			for p := 0; p < i; p++ {
+				x.data[k*i+j] -= a[n*i+p] * x.data[k*p+j]
+			}
+		}
+		for i := n - 1; i >= 0; i-- {
+			for p := i + 1; p < n; p++ {
+				x.data[k*i+j] -= a[n*i+p] * x.data[k*p+j]
+			}
+			x.data[k*i+j] /= a[n*i+i]
+		}
+	}
+	return x, nil
+}
+
+// SolveLower returns new matrix x which is the solution of linear system m*x = b
+// calculated with forward substitution, the matrix is assumed to be lower triangular
+func (m *Matrix) SolveLower(b *Matrix) (*Matrix, error) {
+	return m.solveTriangular(b, true)
+}
+
+// SolveUpper returns new matrix x which is the solution of linear system m*x = b
+// calculated with back substitution, the matrix is assumed to be upper triangular
+func (m *Matrix) SolveUpper(b *Matrix) (*Matrix, error) {
+	return m.solveTriangular(b, false)
+}
+
+func (m *Matrix) solveTriangular(b *Matrix, lower bool) (*Matrix, error) {
+	if err := m.checkSquare(); err != nil {
+		return nil, err
+	}
+	if m.rows != b.rows {
+		return nil, fmt.Errorf("Dimentions of two matrices %dx%d and %dx%d are not compatible for solving", m.rows, m.cols, b.rows, b.cols)
+	}
+	n, k := m.rows, b.cols
+	a := m.Clone().data
+	for i := 0; i < n; i++ {
+		if a[n*i+i] == 0 {
+			return nil, ErrSingular
+		}
+	}
+	x := b.Clone()
+	for j := 0; j < k; j++ {
+		for s := 0; s < n; s++ {
+			i, p0, p1 := s, 0, s
+			if !lower {
+				i, p0, p1 = n-1-s, n-s, n
+			}
+			v := x.data[k*i+j]
+			for p := p0; p < p1; p++ {
+				v -= a[n*i+p] * x.data[k*p+j]
+			}
+			x.data[k*i+j] = v / a[n*i+i]
+		}
+	}
+	return x, nil
+}
+
+// decomposeLU performs in place Doolittle LU decomposition with partial pivoting
+// of n×n row-major slice storing both factors in it, returns row permutation
+// and sign of the permutation, fails when pivot is not greater than tol in magnitude
+func decomposeLU(a []float64, n int, tol float64) ([]int, float64, error) {
+	perm := make([]int, n)
+	for i := range perm {
+		perm[i] = i
+	}
+	sign := float64(1)
+	for k := 0; k < n; k++ {
+		p := k
+		for i := k + 1; i < n; i++ {
+			if math.Abs(a[n*i+k]) > math.Abs(a[n*p+k]) {
+				p = i
+			}
+		}
+		if math.Abs(a[n*p+k]) <= tol {
+			return nil, 0, ErrSingular
+		}
+		if p != k {
+			for j := 0; j < n; j++ {
+				a[n*k+j], a[n*p+j] = a[n*p+j], a[n*k+j]
+			}
+			perm[k], perm[p] = perm[p], perm[k]
+			sign = -sign
+		}
+		for i := k + 1; i < n; i++ {
+			a[n*i+k] /= a[n*k+k]
+			for j := k + 1; j < n; j++ {
+				a[n*i+j] -= a[n*i+k] * a[n*k+j]
+			}
+		}
+	}
+	return perm, sign, nil
+}
+
+// pivotTol returns tolerance under which pivot of elimination of the matrix is considered to be zero,
+// that is eps relative to the maximum absolute row sum so scaling the matrix does not make it singular
+func (m *Matrix) pivotTol() float64 {
+	return eps * m.Norm(math.Inf(1))
+}
+
+// Inverse returns new inverted matrix
+// calculated with Gauss-Jordan elimination with partial pivoting
+func (m *Matrix) Inverse() (*Matrix, error) {
+	if err := m.checkSquare(); err != nil {
+		return nil, err
+	}
+	n := m.rows
+	tol := m.pivotTol()
+	a := m.Clone().data
+	r, _ := Identity(n)
+	b := r.data
+	for k := 0; k < n; k++ {
+		p := k
+		for i := k + 1; i < n; i++ {
+			if math.Abs(a[n*i+k]) > math.Abs(a[n*p+k]) {
+				p = i
+			}
+		}
+		if math.Abs(a[n*p+k]) <= tol {
+			return nil, ErrSingular
+		}
+		if p != k {
+			for j := 0; j < n; j++ {
+				a[n*k+j], a[n*p+j] = a[n*p+j], a[n*k+j]
+				b[n*k+j], b[n*p+j] = b[n*p+j], b[n*k+j]
+			}
+		}
+		f := a[n*k+k]
+		for j := 0; j < n; j++ {
+			a[n*k+j] /= f
+			b[n*k+j] /= f
+		}
+		for i := 0; i < n; i++ {
+			if i == k {
+				continue
+			}
+			f := a[n*i+k]
+			for j := 0; j < n; j++ {
+				a[n*i+j] -= f * a[n*k+j]
+				b[n*i+j] -= f * b[n*k+j]
+			}
+		}
+	}
+	return r, nil
+}
+
+// Hadamard multiplies every element by the corresponding element of the matrix
+func (m *Matrix) Hadamard(x *Matrix) error {
+	if err := m.checkEqualDimentions(x); err != nil {
+		return err
+	}
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			m.set(i, j, m.get(i, j)*x.get(i, j))
+		}
+	}
+	return nil
+}
+
+// Trace returns sum of diagonal elements of the square matrix
+func (m *Matrix) Trace() (float64, error) {
+	if err := m.checkSquare(); err != nil {
+		return 0, err
+	}
+	r := float64(0)
+	for i := 0; i < m.rows; i++ {
+		r += m.get(i, i)
+	}
+	return r, nil
+}
+
+// Mean returns arithmetic mean of all elements in the matrix
+// Mean of the empty matrix is 0
+func (m *Matrix) Mean() float64 {
+	m.RLock()
+	defer m.RUnlock()
+	if len(m.data) == 0 {
+		return 0
+	}
+	r := float64(0)
+	for _, v := range m.data {
+		r += v
+	}
+	return r / float64(len(m.data))
+}
+
+// Variance returns population variance of all elements in the matrix,
+// that is mean of squared deviations from the mean divided by count of elements.
+// Variance of the empty matrix is 0
+func (m *Matrix) Variance() float64 {
+	m.RLock()
+	defer m.RUnlock()
+	_, r := meanVariance(m.data)
+	return r
+}
+
+// meanVariance returns mean and population variance of values, both are 0 for no values
+func meanVariance(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	mean := float64(0)
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	r := float64(0)
+	for _, v := range values {
+		r += (v - mean) * (v - mean)
+	}
+	return mean, r / float64(len(values))
+}
+
+// StdDev returns population standard deviation of all elements in the matrix
+func (m *Matrix) StdDev() float64 {
+	return math.Sqrt(m.Variance())
+}
+
+// Median returns median of all elements in the matrix
+func (m *Matrix) Median() (float64, error) {
+	return m.Percentile(50)
+}
+
+// Percentile returns p-th percentile (0 <= p <= 100) of all elements in the matrix
+// using linear interpolation between closest ranks
+func (m *Matrix) Percentile(p float64) (float64, error) {
+	if !(p >= 0 && p <= 100) {
+		return 0, fmt.Errorf("Percentile %g is out of the range [0, 100]", p)
+	}
+	m.RLock()
+	sorted := make([]float64, len(m.data))
+	copy(sorted, m.data)
+	m.RUnlock()
+	if len(sorted) == 0 {
+		return 0, ErrEmpty
+	}
+	sort.Float64s(sorted)
+	rank := p / 100 * float64(len(sorted)-1)
+	k := int(rank)
+	if k == len(sorted)-1 {
+		return sorted[k], nil
+	}
+	return sorted[k] + (rank-float64(k))*(sorted[k+1]-sorted[k]), nil
+}
+
+// Sum returns sum of all elements in the matrix
+func (m *Matrix) Sum() float64 {
+	m.RLock()
+	defer m.RUnlock()
+	r := float64(0)
+	for _, v := range m.data {
+		r += v
+	}
+	return r
+}
+
+// Max returns maximum element of the matrix
+func (m *Matrix) Max() (float64, error) {
+	m.RLock()
+	defer m.RUnlock()
+	if len(m.data) == 0 {
+		return 0, ErrEmpty
+	}
+	r := m.data[0]
+	for _, v := range m.data[1:] {
+		if v > r {
+			r = v
+		}
+	}
+	return r, nil
+}
+
+// Min returns minimum element of the matrix
+func (m *Matrix) Min() (float64, error) {
+	m.RLock()
+	defer m.RUnlock()
+	if len(m.data) == 0 {
+		return 0, ErrEmpty
+	}
+	r := m.data[0]
+	for _, v := range m.data[1:] {
+		if v < r {
+			r = v
+		}
+	}
+	return r, nil
+}
+
+// SumRows returns new matrix with one column consisting of sums of every row
+func (m *Matrix) SumRows() *Matrix {
+	r := &Matrix{
+		rows: m.rows,
+		cols: 1,
+		data: make([]float64, m.rows),
+	}
+	m.RLock()
+	defer m.RUnlock()
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			r.data[i] += m.data[m.cols*i+j]
+		}
+	}
+	return r
+}
+
+// SumCols returns new matrix with one row consisting of sums of every column
+func (m *Matrix) SumCols() *Matrix {
+	r := &Matrix{
+		rows: 1,
+		cols: m.cols,
+		data: make([]float64, m.cols),
+	}
+	m.RLock()
+	defer m.RUnlock()
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			r.data[j] += m.data[m.cols*i+j]
+		}
+	}
+	return r
+}
+
+// GetRow returns new matrix with one row copied from the i-th row
+func (m *Matrix) GetRow(i int) (*Matrix, error) {
+	if err := m.checkRow(i); err != nil {
+		return nil, err
+	}
+	r := &Matrix{
+		rows: 1,
+		cols: m.cols,
+		data: make([]float64, m.cols),
+	}
+	for j := 0; j < m.cols; j++ {
+		r.set(0, j, m.get(i, j))
+	}
+	return r, nil
+}
+
+// GetCol returns new matrix with one column copied from the j-th column
+func (m *Matrix) GetCol(j int) (*Matrix, error) {
+	if err := m.checkCol(j); err != nil {
+		return nil, err
+	}
+	r := &Matrix{
+		rows: m.rows,
+		cols: 1,
+		data: make([]float64, m.rows),
+	}
+	for i := 0; i < m.rows; i++ {
+		r.set(i, 0, m.get(i, j))
+	}
+	return r, nil
+}
+
+// SwapRows swaps i-th and j-th rows of the matrix
+func (m *Matrix) SwapRows(i, j int) error {
+	if err := m.checkRow(i); err != nil {
+		return err
+	}
+	if err := m.checkRow(j); err != nil {
+		return err
+	}
+	m.Lock()
+	defer m.Unlock()
+	for k := 0; k < m.cols; k++ {
+		m.data[m.cols*i+k], m.data[m.cols*j+k] = m.data[m.cols*j+k], m.data[m.cols*i+k]
+	}
+	return nil
+}
+
+// SwapCols swaps i-th and j-th columns of the matrix
+func (m *Matrix) SwapCols(i, j int) error {
+	if err := m.checkCol(i); err != nil {
+		return err
+	}
+	if err := m.checkCol(j); err != nil {
+		return err
+	}
+	m.Lock()
+	defer m.Unlock()
+	for k := 0; k < m.rows; k++ {
+		m.data[m.cols*k+i], m.data[m.cols*k+j] = m.data[m.cols*k+j], m.data[m.cols*k+i]
+	}
+	return nil
+}
+
+// Equals reports whether the matrices have equal dimentions and bit-for-bit equal elements
+func (m *Matrix) Equals(x *Matrix) bool {
+	if m.checkEqualDimentions(x) != nil {
+		return false
+	}
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			if math.Float64bits(m.get(i, j)) != math.Float64bits(x.get(i, j)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// EqualsApprox reports whether the matrices have equal dimentions
+// and elements which differ not more than by given absolute tolerance
+func (m *Matrix) EqualsApprox(x *Matrix, tol float64) bool {
+	if m.checkEqualDimentions(x) != nil {
+		return false
+	}
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			if !(math.Abs(m.get(i, j)-x.get(i, j)) <= tol) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// DiffEntry is a pair of differing values at position (I, J) of compared matrices
+type DiffEntry struct {
+	I, J      int
+	Got, Want float64
+}
+
+// Diff returns entries at every position where elements of the matrix and given one
+// differ more than by given absolute tolerance. Empty result means the matrices match
+func (m *Matrix) Diff(x *Matrix, tol float64) ([]DiffEntry, error) {
+	if err := m.checkEqualDimentions(x); err != nil {
+		return nil, err
+	}
+	var r []DiffEntry
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			got, want := m.get(i, j), x.get(i, j)
+			if !(math.Abs(got-want) <= tol) {
+				r = append(r, DiffEntry{I: i, J: j, Got: got, Want: want})
+			}
+		}
+	}
+	return r, nil
+}
+
+// Reshape returns new matrix with given dimentions and the same elements in row-major order
+func (m *Matrix) Reshape(rows, cols int) (*Matrix, error) {
+	r, err := New(rows, cols)
+	if err != nil {
+		return nil, err
+	}
+	if rows*cols != m.rows*m.cols {
+		return nil, fmt.Errorf("Dimentions %dx%d are not compatible with %dx%d", rows, cols, m.rows, m.cols)
+	}
+	m.RLock()
+	copy(r.data, m.data)
+	m.RUnlock()
+	return r, nil
+}
+
+// HStack returns new matrix joined from the matrix and given one side by side
+func (m *Matrix) HStack(x *Matrix) (*Matrix, error) {
+	if m.rows != x.rows {
+		return nil, fmt.Errorf("Rows count of two matrices %dx%d and %dx%d are not equal", m.rows, m.cols, x.rows, x.cols)
+	}
+	r := &Matrix{
+		rows: m.rows,
+		cols: m.cols + x.cols,
+		data: make([]float64, m.rows*(m.cols+x.cols)),
+	}
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			r.set(i, j, m.get(i, j))
+		}
+		for j := 0; j < x.cols; j++ {
+			r.set(i, m.cols+j, x.get(i, j))
+		}
+	}
+	return r, nil
+}
+
+// VStack returns new matrix joined from the matrix and given one top to bottom
+func (m *Matrix) VStack(x *Matrix) (*Matrix, error) {
+	if m.cols != x.cols {
+		return nil, fmt.Errorf("Columns count of two matrices %dx%d and %dx%d are not equal", m.rows, m.cols, x.rows, x.cols)
+	}
+	r := &Matrix{
+		rows: m.rows + x.rows,
+		cols: m.cols,
+		data: make([]float64, (m.rows+x.rows)*m.cols),
+	}
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			r.set(i, j, m.get(i, j))
+		}
+	}
+	for i := 0; i < x.rows; i++ {
+		for j := 0; j < x.cols; j++ {
+			r.set(m.rows+i, j, x.get(i, j))
+		}
+	}
+	return r, nil
+}
+
+// Submatrix returns new matrix copied from rows r0..r1-1 and columns c0..c1-1
+func (m *Matrix) Submatrix(r0, c0, r1, c1 int) (*Matrix, error) {
+	if r0 < 0 || c0 < 0 || r0 > r1 || c0 > c1 || r1 > m.rows || c1 > m.cols {
+		return nil, fmt.Errorf("Bounds (%d, %d):(%d, %d) are invalid for matrix %dx%d", r0, c0, r1, c1, m.rows, m.cols)
+	}
+	r := &Matrix{
+		rows: r1 - r0,
+		cols: c1 - c0,
+		data: make([]float64, (r1-r0)*(c1-c0)),
+	}
+	for i := r0; i < r1; i++ {
+		for j := c0; j < c1; j++ {
+			r.set(i-r0, j-c0, m.get(i, j))
+		}
+	}
+	return r, nil
+}
+
+// Negate flips the sign of every element in the matrix
+func (m *Matrix) Negate() {
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			m.set(i, j, -m.get(i, j))
+		}
+	}
+}
+
+// Map returns new matrix with function applied to every element of the matrix
+func (m *Matrix) Map(f func(i, j int, v float64) float64) *Matrix {
+	r := &Matrix{
+		rows: m.rows,
+		cols: m.cols,
+		data: make([]float64, m.rows*m.cols),
+	}
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			r.set(i, j, f(i, j, m.get(i, j)))
+		}
+	}
+	return r
+}
+
+// FrobeniusNorm returns square root of the sum of squares of all elements in the matrix
+func (m *Matrix) FrobeniusNorm() float64 {
+	m.RLock()
+	defer m.RUnlock()
+	r := float64(0)
+	for _, v := range m.data {
+		r += v * v
+	}
+	return math.Sqrt(r)
+}
+
+// Norm returns norm of the matrix of given kind:
+// p = 1 is the maximum absolute column sum,
+// p = math.Inf(1) is the maximum absolute row sum,
+// p = 2 is the Frobenius norm treating the matrix as a vector of elements.
+// Other values of p are not supported and NaN is returned
+func (m *Matrix) Norm(p float64) float64 {
+	if p == 2 {
+		return m.FrobeniusNorm()
+	}
+	if p != 1 && !math.IsInf(p, 1) {
+		return math.NaN()
+	}
+	m.RLock()
+	defer m.RUnlock()
+	r := float64(0)
+	if p == 1 {
+		for j := 0; j < m.cols; j++ {
+			s := float64(0)
+			for i := 0; i < m.rows; i++ {
+				s += math.Abs(m.data[m.cols*i+j])
+			}
+			r = math.Max(r, s)
+		}
+		return r
+	}
+	for i := 0; i < m.rows; i++ {
+		s := float64(0)
+		for j := 0; j < m.cols; j++ {
+			s += math.Abs(m.data[m.cols*i+j])
+		}
+		r = math.Max(r, s)
+	}
+	return r
+}
+
+// Diag returns elements of the main diagonal of the matrix
+func (m *Matrix) Diag() []float64 {
+	n := m.rows
+	if m.cols < n {
+		n = m.cols
+	}
+	d := make([]float64, n)
+	for i := range d {
+		d[i] = m.get(i, i)
+	}
+	return d
+}
+
+// Kron returns new matrix which is the Kronecker product of the matrix and given one
+func (m *Matrix) Kron(x *Matrix) *Matrix {
+	r := &Matrix{
+		rows: m.rows * x.rows,
+		cols: m.cols * x.cols,
+		data: make([]float64, m.rows*x.rows*m.cols*x.cols),
+	}
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			v := m.get(i, j)
+			for p := 0; p < x.rows; p++ {
+				for q := 0; q < x.cols; q++ {
+					r.set(i*x.rows+p, j*x.cols+q, v*x.get(p, q))
+				}
+			}
+		}
+	}
+	return r
+}
+
+// Outer returns new matrix which is the outer product of two vectors
+// given as matrices with one column or one row
+func (m *Matrix) Outer(x *Matrix) (*Matrix, error) {
+	if err := m.checkVector(); err != nil {
+		return nil, err
+	}
+	if err := x.checkVector(); err != nil {
+		return nil, err
+	}
+	a, b := m.Clone().data, x.Clone().data
+	r := &Matrix{
+		rows: len(a),
+		cols: len(b),
+		data: make([]float64, len(a)*len(b)),
+	}
+	for i, u := range a {
+		for j, v := range b {
+			r.data[len(b)*i+j] = u * v
+		}
+	}
+	return r, nil
+}
+
+// Rank1Update adds alpha*x*y^T to the matrix in place without allocating the outer product,
+// x must have one column and as many rows as the matrix, y must have one column and as many rows as columns of the matrix
+func (m *Matrix) Rank1Update(alpha float64, x, y *Matrix) error {
+	if x.cols != 1 || x.rows != m.rows || y.cols != 1 || y.rows != m.cols {
+		return fmt.Errorf("Dimentions of vectors %dx%d and %dx%d are not compatible with matrix %dx%d", x.rows, x.cols, y.rows, y.cols, m.rows, m.cols)
+	}
+	a, b := x.Clone().data, y.Clone().data
+	m.Lock()
+	defer m.Unlock()
+	for i, u := range a {
+		u *= alpha
+		for j, v := range b {
+			m.data[m.cols*i+j] += u * v
+		}
+	}
+	return nil
+}
+
+// Power returns new square matrix raised to integer power n
+// calculated with exponentiation by squaring.
+// Negative power is calculated as power of the inverted matrix
+func (m *Matrix) Power(n int) (*Matrix, error) {
+	if err := m.checkSquare(); err != nil {
+		return nil, err
+	}
+	b := m
+	if n < 0 {
+		inv, err := m.Inverse()
+		if err != nil {
+			return nil, err
+		}
+		b, n = inv, -n
+	}
+	r, _ := Identity(m.rows)
+	for ; n > 0; n >>= 1 {
+		if n&1 == 1 {
+			r, _ = r.Mul(b)
+		}
+		if n > 1 {
+			b, _ = b.Mul(b)
+		}
+	}
+	return r, nil
+}
+
+// Fill sets every element in the matrix to given value
+func (m *Matrix) Fill(v float64) {
+	m.Lock()
+	defer m.Unlock()
+	for k := range m.data {
+		m.data[k] = v
+	}
+}
+
+// Zero sets every element in the matrix to zero
+func (m *Matrix) Zero() {
+	m.Fill(0)
+}
+
+// Clamp bounds every element in the matrix to the interval [min, max].
+// NaN elements are left untouched
+func (m *Matrix) Clamp(min, max float64) error {
+	if min > max {
+		return fmt.Errorf("Lower bound %g must not be greater than upper bound %g", min, max)
+	}
+	m.Lock()
+	defer m.Unlock()
+	for k, v := range m.data {
+		if v < min {
+			m.data[k] = min
+		} else if v > max {
+			m.data[k] = max
+		}
+	}
+	return nil
+}
+
+// HasNaN reports whether the matrix contains NaN element
+func (m *Matrix) HasNaN() bool {
+	m.RLock()
+	defer m.RUnlock()
+	for _, v := range m.data {
+		if math.IsNaN(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasInf reports whether the matrix contains infinite element
+func (m *Matrix) HasInf() bool {
+	m.RLock()
+	defer m.RUnlock()
+	for _, v := range m.data {
+		if math.IsInf(v, 0) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsFinite reports whether the matrix contains neither NaN nor infinite elements
+func (m *Matrix) IsFinite() bool {
+	m.RLock()
+	defer m.RUnlock()
+	for _, v := range m.data {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// Rank returns numerical rank of the matrix which is the count of pivots
+// greater than tol in magnitude found by Gaussian elimination with partial pivoting
+func (m *Matrix) Rank(tol float64) int {
+	r, _ := echelon(m.Clone().data, m.rows, m.cols, tol, false)
+	return r
+}
+
+// RREF returns new matrix which is the reduced row echelon form of the matrix
+func (m *Matrix) RREF() *Matrix {
+	r := m.Clone()
+	echelon(r.data, r.rows, r.cols, eps, true)
+	return r
+}
+
+// ForwardEliminate returns new upper triangular matrix calculated from the square matrix
+// with forward elimination with partial pivoting and for the k-th pivot the index of the row
+// swapped with the row k, equal to k when no swap was made. Columns without pivot are skipped,
+// so for singular matrix count of swaps is equal to the rank and less than count of rows
+func (m *Matrix) ForwardEliminate() (*Matrix, []int, error) {
+	if err := m.checkSquare(); err != nil {
+		return nil, nil, err
+	}
+	r := m.Clone()
+	_, swaps := echelon(r.data, r.rows, r.cols, eps, false)
+	return r, swaps, nil
+}
+
+// echelon performs in place Gaussian elimination with partial pivoting
+// of rows×cols row-major slice skipping columns with pivots not greater than tol in magnitude,
+// reduces to reduced row echelon form if reduced is true and returns count of pivots
+// and for every pivot the index of the row swapped with the pivot row.
+// Eliminated elements are set to exact zero rather than left with rounding error
+func echelon(a []float64, rows, cols int, tol float64, reduced bool) (int, []int) {
+	r := 0
+	swaps := []int{}
+	for c := 0; c < cols && r < rows; c++ {
+		p := r
+		for i := r + 1; i < rows; i++ {
+			if math.Abs(a[cols*i+c]) > math.Abs(a[cols*p+c]) {
+				p = i
+			}
+		}
+		if math.Abs(a[cols*p+c]) <= tol {
+			continue
+		}
+		if p != r {
+			for j := 0; j < cols; j++ {
+				a[cols*r+j], a[cols*p+j] = a[cols*p+j], a[cols*r+j]
+			}
+		}
+		swaps = append(swaps, p)
+		if reduced {
+			f := a[cols*r+c]
+			for j := c; j < cols; j++ {
+				a[cols*r+j] /= f
+			}
+		}
+		for i := 0; i < rows; i++ {
+			if i == r || (i < r && !reduced) {
+				continue
+			}
+			f := a[cols*i+c] / a[cols*r+c]
+			for j := c + 1; j < cols; j++ {
+				a[cols*i+j] -= f * a[cols*r+j]
+			}
+			a[cols*i+c] = 0
+		}
+		r++
+	}
+	return r, swaps
+}
+
+// ApplyRow applies function to every element in the i-th row of the matrix
+func (m *Matrix) ApplyRow(i int, f func(j int, v float64) float64) error {
+	if err := m.checkRow(i); err != nil {
+		return err
+	}
+	for j := 0; j < m.cols; j++ {
+		m.set(i, j, f(j, m.get(i, j)))
+	}
+	return nil
+}
+
+// ApplyCol applies function to every element in the j-th column of the matrix
+func (m *Matrix) ApplyCol(j int, f func(i int, v float64) float64) error {
+	if err := m.checkCol(j); err != nil {
+		return err
+	}
+	for i := 0; i < m.rows; i++ {
+		m.set(i, j, f(i, m.get(i, j)))
+	}
+	return nil
+}
+
+// AddRow adds the matrix with one row to every row of the matrix
+func (m *Matrix) AddRow(v *Matrix) error {
+	if v.rows != 1 || v.cols != m.cols {
+		return fmt.Errorf("Dimentions %dx%d must be 1x%d to be added to every row of matrix %dx%d", v.rows, v.cols, m.cols, m.rows, m.cols)
+	}
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			m.set(i, j, m.get(i, j)+v.get(0, j))
+		}
+	}
+	return nil
+}
+
+// AddCol adds the matrix with one column to every column of the matrix
+func (m *Matrix) AddCol(v *Matrix) error {
+	if v.rows != m.rows || v.cols != 1 {
+		return fmt.Errorf("Dimentions %dx%d must be %dx1 to be added to every column of matrix %dx%d", v.rows, v.cols, m.rows, m.rows, m.cols)
+	}
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			m.set(i, j, m.get(i, j)+v.get(i, 0))
+		}
+	}
+	return nil
+}
+
+// Div divides every element by the corresponding element of the matrix.
+// Division by zero element follows IEEE 754 and produces infinity or NaN
+func (m *Matrix) Div(x *Matrix) error {
+	if err := m.checkEqualDimentions(x); err != nil {
+		return err
+	}
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			m.set(i, j, m.get(i, j)/x.get(i, j))
+		}
+	}
+	return nil
+}
+
+// DivScalar divides every element in the matrix by given number
+func (m *Matrix) DivScalar(n float64) error {
+	if n == 0 {
+		return errors.New("Division by zero")
+	}
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			m.set(i, j, m.get(i, j)/n)
+		}
+	}
+	return nil
+}
+
+// QR returns orthogonal matrix q and upper triangular matrix r such that q*r equals the matrix
+// calculated with Householder reflections, the matrix must not have more columns than rows
+func (m *Matrix) QR() (q, r *Matrix, err error) {
+	if m.rows < m.cols {
+		return nil, nil, fmt.Errorf("Matrix %dx%d must not have more columns than rows", m.rows, m.cols)
+	}
+	rows, cols := m.rows, m.cols
+	r = m.Clone()
+	q, _ = Identity(rows)
+	v := make([]float64, rows)
+	for k := 0; k < cols && k < rows-1; k++ {
+		norm := float64(0)
+		for i := k; i < rows; i++ {
+			norm += r.data[cols*i+k] * r.data[cols*i+k]
+		}
+		norm = math.Sqrt(norm)
+		if norm == 0 {
+			continue
+		}
+		alpha := -norm
+		if r.data[cols*k+k] < 0 {
+			alpha = norm
+		}
+		vnorm := float64(0)
+		for i := k; i < rows; i++ {
+			v[i] = r.data[cols*i+k]
+			if i == k {
+				v[i] -= alpha
+			}
+			vnorm += v[i] * v[i]
+		}
+		vnorm = math.Sqrt(vnorm)
+		for i := k; i < rows; i++ {
+			v[i] /= vnorm
+		}
+		for j := 0; j < cols; j++ {
+			d := float64(0)
+			for i := k; i < rows; i++ {
+				d += v[i] * r.data[cols*i+j]
+			}
+			for i := k; i < rows; i++ {
+				r.data[cols*i+j] -= 2 * v[i] * d
+			}
+		}
+		for i := 0; i < rows; i++ {
+			d := float64(0)
+			for j := k; j < rows; j++ {
+				d += q.data[rows*i+j] * v[j]
+			}
+			for j := k; j < rows; j++ {
+				q.data[rows*i+j] -= 2 * d * v[j]
+			}
+		}
+		for i := k + 1; i < rows; i++ {
+			r.data[cols*i+k] = 0
+		}
+	}
+	return q, r, nil
+}
+
+// Cholesky returns lower triangular matrix l such that l*l.T() equals
+// the symmetric positive definite matrix
+func (m *Matrix) Cholesky() (*Matrix, error) {
+	if err := m.checkSquare(); err != nil {
+		return nil, err
+	}
+	if !m.IsSymmetric(eps * m.Norm(math.Inf(1))) {
+		return nil, ErrNotSymmetric
+	}
+	n := m.rows
+	a := m.Clone().data
+	l, _ := New(n, n)
+	for j := 0; j < n; j++ {
+		d := a[n*j+j]
+		for k := 0; k < j; k++ {
+			d -= l.data[n*j+k] * l.data[n*j+k]
+		}
+		if d <= 0 {
+			return nil, ErrNotPositiveDefinite
+		}
+		l.data[n*j+j] = math.Sqrt(d)
+		for i := j + 1; i < n; i++ {
+			v := a[n*i+j]
+			for k := 0; k < j; k++ {
+				v -= l.data[n*i+k] * l.data[n*j+k]
+			}
+			l.data[n*i+j] = v / l.data[n*j+j]
+		}
+	}
+	return l, nil
+}
+
+// LstSq returns new matrix x minimizing norm of m*x - b
+// calculated with QR decomposition, every column of b is a separate right-hand side
+func (m *Matrix) LstSq(b *Matrix) (*Matrix, error) {
+	if m.rows != b.rows {
+		return nil, fmt.Errorf("Dimentions of two matrices %dx%d and %dx%d are not compatible for solving", m.rows, m.cols, b.rows, b.cols)
+	}
+	q, r, err := m.QR()
+	if err != nil {
+		return nil, err
+	}
+	y, _ := q.T().Mul(b)
+	n, k := m.cols, b.cols
+	x := &Matrix{
+		rows: n,
+		cols: k,
+		data: make([]float64, n*k),
+	}
+	for j := 0; j < k; j++ {
+		for i := n - 1; i >= 0; i-- {
+			if math.Abs(r.data[n*i+i]) < eps {
+				return nil, ErrSingular
+			}
+			v := y.data[k*i+j]
+			for p := i + 1; p < n; p++ {
+				v -= r.data[n*i+p] * x.data[k*p+j]
+			}
+			x.data[k*i+j] = v / r.data[n*i+i]
+		}
+	}
+	return x, nil
+}
+
+// LDL returns lower unit triangular matrix l and diagonal matrix d such that l*d*l.T()
+// equals the symmetric matrix, calculated without pivoting
+func (m *Matrix) LDL() (l, d *Matrix, err error) {
+	if err := m.checkSquare(); err != nil {
+		return nil, nil, err
+	}
+	if !m.IsSymmetric(eps * m.Norm(math.Inf(1))) {
+		return nil, nil, ErrNotSymmetric
+	}
+	n := m.rows
+	a := m.Clone().data
+	l, _ = Identity(n)
+	d, _ = New(n, n)
+	for j := 0; j < n; j++ {
+		v := a[n*j+j]
+		for k := 0; k < j; k++ {
+			v -= l.data[n*j+k] * l.data[n*j+k] * d.data[n*k+k]
+		}
+		if math.Abs(v) < eps {
+			return nil, nil, ErrSingular
+		}
+		d.data[n*j+j] = v
+		for i := j + 1; i < n; i++ {
+			u := a[n*i+j]
+			for k := 0; k < j; k++ {
+				u -= l.data[n*i+k] * l.data[n*j+k] * d.data[n*k+k]
+			}
+			l.data[n*i+j] = u / v
+		}
+	}
+	return l, d, nil
+}
+
+// IsSquare reports whether the matrix has equal count of rows and columns
+func (m *Matrix) IsSquare() bool {
+	return m.rows == m.cols
+}
+
+// IsSymmetric reports whether the matrix is square and equals its transpose within given tolerance
+func (m *Matrix) IsSymmetric(tol float64) bool {
+	if !m.IsSquare() {
+		return false
+	}
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < i; j++ {
+			if !(math.Abs(m.get(i, j)-m.get(j, i)) <= tol) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// IsUpperTriangular reports whether the matrix is square
+// and all elements below the main diagonal are within given tolerance of zero
+func (m *Matrix) IsUpperTriangular(tol float64) bool {
+	if !m.IsSquare() {
+		return false
+	}
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < i; j++ {
+			if !(math.Abs(m.get(i, j)) <= tol) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// IsLowerTriangular reports whether the matrix is square
+// and all elements above the main diagonal are within given tolerance of zero
+func (m *Matrix) IsLowerTriangular(tol float64) bool {
+	if !m.IsSquare() {
+		return false
+	}
+	for i := 0; i < m.rows; i++ {
+		for j := i + 1; j < m.cols; j++ {
+			if !(math.Abs(m.get(i, j)) <= tol) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// IsDiagonal reports whether all elements off the main diagonal are within given tolerance of zero
+func (m *Matrix) IsDiagonal(tol float64) bool {
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			if i != j && !(math.Abs(m.get(i, j)) <= tol) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// DeleteRow returns new matrix without the i-th row
+func (m *Matrix) DeleteRow(i int) (*Matrix, error) {
+	if err := m.checkRow(i); err != nil {
+		return nil, err
+	}
+	r := &Matrix{
+		rows: m.rows - 1,
+		cols: m.cols,
+		data: make([]float64, (m.rows-1)*m.cols),
+	}
+	m.RLock()
+	defer m.RUnlock()
+	copy(r.data, m.data[:m.cols*i])
+	copy(r.data[m.cols*i:], m.data[m.cols*(i+1):])
+	return r, nil
+}
+
+// DeleteCol returns new matrix without the j-th column
+func (m *Matrix) DeleteCol(j int) (*Matrix, error) {
+	if err := m.checkCol(j); err != nil {
+		return nil, err
+	}
+	r := &Matrix{
+		rows: m.rows,
+		cols: m.cols - 1,
+		data: make([]float64, m.rows*(m.cols-1)),
+	}
+	m.RLock()
+	defer m.RUnlock()
+	for i := 0; i < m.rows; i++ {
+		copy(r.data[r.cols*i:], m.data[m.cols*i:m.cols*i+j])
+		copy(r.data[r.cols*i+j:], m.data[m.cols*i+j+1:m.cols*(i+1)])
+	}
+	return r, nil
+}
+
+// InsertRow returns new matrix with given values inserted as the i-th row
+func (m *Matrix) InsertRow(i int, values []float64) (*Matrix, error) {
+	if i < 0 || i > m.rows {
+		return nil, fmt.Errorf("Row %d is out of the range (0:%d)", i, m.rows)
+	}
+	if len(values) != m.cols {
+		return nil, fmt.Errorf("Row has %d elements, expected %d", len(values), m.cols)
+	}
+	r := &Matrix{
+		rows: m.rows + 1,
+		cols: m.cols,
+		data: make([]float64, (m.rows+1)*m.cols),
+	}
+	m.RLock()
+	defer m.RUnlock()
+	copy(r.data, m.data[:m.cols*i])
+	copy(r.data[m.cols*i:], values)
+	copy(r.data[m.cols*(i+1):], m.data[m.cols*i:])
+	return r, nil
+}
+
+// InsertCol returns new matrix with given values inserted as the j-th column
+func (m *Matrix) InsertCol(j int, values []float64) (*Matrix, error) {
+	if j < 0 || j > m.cols {
+		return nil, fmt.Errorf("Column %d is out of the range (0:%d)", j, m.cols)
+	}
+	if len(values) != m.rows {
+		return nil, fmt.Errorf("Column has %d elements, expected %d", len(values), m.rows)
+	}
+	r := &Matrix{
+		rows: m.rows,
+		cols: m.cols + 1,
+		data: make([]float64, m.rows*(m.cols+1)),
+	}
+	m.RLock()
+	defer m.RUnlock()
+	for i := 0; i < m.rows; i++ {
+		copy(r.data[r.cols*i:], m.data[m.cols*i:m.cols*i+j])
+		r.data[r.cols*i+j] = values[i]
+		copy(r.data[r.cols*i+j+1:], m.data[m.cols*i+j:m.cols*(i+1)])
+	}
+	return r, nil
+}
+
+// EigenSymmetric returns eigenvalues of the symmetric matrix in ascending order
+// and matrix with corresponding eigenvectors as columns
+// calculated with cyclic Jacobi rotations
+func (m *Matrix) EigenSymmetric() (values []float64, vectors *Matrix, err error) {
+	if !m.IsSymmetric(eps * m.Norm(math.Inf(1))) {
+		return nil, nil, ErrNotSymmetric
+	}
+	n := m.rows
+	a := m.Clone().data
+	v, _ := Identity(n)
+	norm := m.FrobeniusNorm()
+	for sweep := 0; sweep < 100; sweep++ {
+		off := float64(0)
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				off += a[n*p+q] * a[n*p+q]
+			}
+		}
+		if math.Sqrt(off) <= eps*norm {
+			break
+		}
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				if a[n*p+q] == 0 {
+					continue
+				}
+				theta := (a[n*q+q] - a[n*p+p]) / (2 * a[n*p+q])
+				t := 1 / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				if theta < 0 {
+					t = -t
+				}
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+				for k := 0; k < n; k++ {
+					kp, kq := a[n*k+p], a[n*k+q]
+					a[n*k+p], a[n*k+q] = c*kp-s*kq, s*kp+c*kq
+				}
+				for k := 0; k < n; k++ {
+					pk, qk := a[n*p+k], a[n*q+k]
+					a[n*p+k], a[n*q+k] = c*pk-s*qk, s*pk+c*qk
+				}
+				for k := 0; k < n; k++ {
+					kp, kq := v.data[n*k+p], v.data[n*k+q]
+					v.data[n*k+p], v.data[n*k+q] = c*kp-s*kq, s*kp+c*kq
+				}
+			}
+		}
+	}
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(x, y int) bool {
+		return a[n*order[x]+order[x]] < a[n*order[y]+order[y]]
+	})
+	values = make([]float64, n)
+	vectors, _ = New(n, n)
+	for j, o := range order {
+		values[j] = a[n*o+o]
+		for i := 0; i < n; i++ {
+			vectors.data[n*i+j] = v.data[n*i+o]
+		}
+	}
+	return values, vectors, nil
+}
+
+// Argmax returns position of the first maximum element of the matrix
+func (m *Matrix) Argmax() (i, j int, err error) {
+	m.RLock()
+	defer m.RUnlock()
+	if len(m.data) == 0 {
+		return 0, 0, ErrEmpty
+	}
+	p := 0
+	for k, v := range m.data {
+		if v > m.data[p] {
+			p = k
+		}
+	}
+	return p / m.cols, p % m.cols, nil
+}
+
+// Argmin returns position of the first minimum element of the matrix
+func (m *Matrix) Argmin() (i, j int, err error) {
+	m.RLock()
+	defer m.RUnlock()
+	if len(m.data) == 0 {
+		return 0, 0, ErrEmpty
+	}
+	p := 0
+	for k, v := range m.data {
+		if v < m.data[p] {
+			p = k
+		}
+	}
+	return p / m.cols, p % m.cols, nil
+}
+
+// SoftmaxRows returns new matrix with softmax function applied to every row of the matrix
+func (m *Matrix) SoftmaxRows() *Matrix {
+	r := m.Clone()
+	for i := 0; i < r.rows; i++ {
+		row := r.data[r.cols*i : r.cols*(i+1)]
+		mx := math.Inf(-1)
+		for _, v := range row {
+			mx = math.Max(mx, v)
+		}
+		sum := float64(0)
+		for j, v := range row {
+			row[j] = math.Exp(v - mx)
+			sum += row[j]
+		}
+		for j := range row {
+			row[j] /= sum
+		}
+	}
+	return r
+}
+
+// Cond returns condition number of the square matrix in 2-norm
+// which is the ratio of the largest singular value to the smallest one
+func (m *Matrix) Cond() (float64, error) {
+	if err := m.checkSquare(); err != nil {
+		return 0, err
+	}
+	if m.rows == 0 {
+		return 0, ErrEmpty
+	}
+	sv := m.singularValues()
+	smax, smin := sv[0], sv[0]
+	for _, v := range sv[1:] {
+		smax = math.Max(smax, v)
+		smin = math.Min(smin, v)
+	}
+	if smin <= eps*smax {
+		return 0, ErrSingular
+	}
+	return smax / smin, nil
+}
+
+// singularValues returns singular values of the matrix in no particular order
+// calculated with one-sided Jacobi rotations of columns
+func (m *Matrix) singularValues() []float64 {
+	rows, cols := m.rows, m.cols
+	u := m.Clone().data
+	for sweep := 0; sweep < 100; sweep++ {
+		rotated := false
+		for p := 0; p < cols; p++ {
+			for q := p + 1; q < cols; q++ {
+				alpha, beta, gamma := float64(0), float64(0), float64(0)
+				for k := 0; k < rows; k++ {
+					alpha += u[cols*k+p] * u[cols*k+p]
+					beta += u[cols*k+q] * u[cols*k+q]
+					gamma += u[cols*k+p] * u[cols*k+q]
+				}
+				if math.Abs(gamma) <= eps*math.Sqrt(alpha*beta) {
+					continue
+				}
+				rotated = true
+				zeta := (beta - alpha) / (2 * gamma)
+				t := 1 / (math.Abs(zeta) + math.Sqrt(1+zeta*zeta))
+				if zeta < 0 {
+					t = -t
+				}
+				c := 1 / math.Sqrt(1+t*t)
+				s := c * t
+				for k := 0; k < rows; k++ {
+					kp, kq := u[cols*k+p], u[cols*k+q]
+					u[cols*k+p], u[cols*k+q] = c*kp-s*kq, s*kp+c*kq
+				}
+			}
+		}
+		if !rotated {
+			break
+		}
+	}
+	sv := make([]float64, cols)
+	for j := range sv {
+		for k := 0; k < rows; k++ {
+			sv[j] += u[cols*k+j] * u[cols*k+j]
+		}
+		sv[j] = math.Sqrt(sv[j])
+	}
+	return sv
+}
+
+// NormalizeFrobenius scales the matrix to have Frobenius norm equal to 1
+func (m *Matrix) NormalizeFrobenius() error {
+	n := m.FrobeniusNorm()
+	if n == 0 {
+		return errors.New("Frobenius norm of the matrix is zero")
+	}
+	return m.DivScalar(n)
+}
+
+// NormalizeTrace scales the square matrix to have trace equal to 1
+func (m *Matrix) NormalizeTrace() error {
+	t, err := m.Trace()
+	if err != nil {
+		return err
+	}
+	if t == 0 {
+		return errors.New("Trace of the matrix is zero")
+	}
+	return m.DivScalar(t)
+}
+
+// Standardize transforms the matrix in place to zero mean and unit standard deviation of all elements.
+// Matrix with zero standard deviation is left untouched
+func (m *Matrix) Standardize() {
+	m.Lock()
+	defer m.Unlock()
+	mean, v := meanVariance(m.data)
+	if v == 0 {
+		return
+	}
+	std := math.Sqrt(v)
+	for k, v := range m.data {
+		m.data[k] = (v - mean) / std
+	}
+}
+
+// StandardizeCols transforms every column of the matrix in place to zero mean and unit standard deviation.
+// Columns with zero standard deviation are left untouched
+func (m *Matrix) StandardizeCols() {
+	m.Lock()
+	defer m.Unlock()
+	for j := 0; j < m.cols; j++ {
+		mean := float64(0)
+		for i := 0; i < m.rows; i++ {
+			mean += m.data[m.cols*i+j]
+		}
+		mean /= float64(m.rows)
+		std := float64(0)
+		for i := 0; i < m.rows; i++ {
+			d := m.data[m.cols*i+j] - mean
+			std += d * d
+		}
+		std = math.Sqrt(std / float64(m.rows))
+		if std == 0 {
+			continue
+		}
+		for i := 0; i < m.rows; i++ {
+			m.data[m.cols*i+j] = (m.data[m.cols*i+j] - mean) / std
+		}
+	}
+}
+
+// TraceMul returns trace of the product of the matrix and given one
+// without calculating the product itself
+func (m *Matrix) TraceMul(x *Matrix) (float64, error) {
+	if err := m.checkMulDimentions(x); err != nil {
+		return 0, err
+	}
+	if m.rows != x.cols {
+		return 0, fmt.Errorf("Product of two matrices %dx%d and %dx%d is not square", m.rows, m.cols, x.rows, x.cols)
+	}
+	r := float64(0)
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			r += m.get(i, j) * x.get(j, i)
+		}
+	}
+	return r, nil
+}
+
+// Greater returns new matrix with 1 where element of the matrix is greater than given number and 0 elsewhere
+func (m *Matrix) Greater(n float64) *Matrix {
+	return m.Map(func(i, j int, v float64) float64 {
+		if v > n {
+			return 1
+		}
+		return 0
+	})
+}
+
+// Less returns new matrix with 1 where element of the matrix is less than given number and 0 elsewhere
+func (m *Matrix) Less(n float64) *Matrix {
+	return m.Map(func(i, j int, v float64) float64 {
+		if v < n {
+			return 1
+		}
+		return 0
+	})
+}
+
+// EqualScalar returns new matrix with 1 where element of the matrix equals given number and 0 elsewhere
+func (m *Matrix) EqualScalar(n float64) *Matrix {
+	return m.Map(func(i, j int, v float64) float64 {
+		if v == n {
+			return 1
+		}
+		return 0
+	})
+}
+
+// Where sets elements to given value at positions where the mask has non-zero elements
+func (m *Matrix) Where(mask *Matrix, value float64) error {
+	if err := m.checkEqualDimentions(mask); err != nil {
+		return err
+	}
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			if mask.get(i, j) != 0 {
+				m.set(i, j, value)
+			}
+		}
+	}
+	return nil
+}
+
+// Adjugate returns new matrix which is the transpose of the cofactor matrix of the square matrix
+func (m *Matrix) Adjugate() (*Matrix, error) {
+	if err := m.checkSquare(); err != nil {
+		return nil, err
+	}
+	n := m.rows
+	r, _ := New(n, n)
+	if n == 1 {
+		r.data[0] = 1
+		return r, nil
+	}
+	for i := 0; i < n; i++ {
+		x, _ := m.DeleteRow(i)
+		for j := 0; j < n; j++ {
+			y, _ := x.DeleteCol(j)
+			d, _ := y.Determinant()
+			if (i+j)%2 == 1 {
+				d = -d
+			}
+			r.data[n*j+i] = d
+		}
+	}
+	return r, nil
+}
+
+// VStackAll returns new matrix joined from given matrices top to bottom
+func VStackAll(ms ...*Matrix) (*Matrix, error) {
+	r := &Matrix{data: []float64{}}
+	if len(ms) == 0 {
+		return r, nil
+	}
+	r.cols = ms[0].cols
+	for k, x := range ms {
+		if x.cols != r.cols {
+			return nil, fmt.Errorf("Columns count %d of matrix %d is not equal to %d", x.cols, k, r.cols)
+		}
+		r.rows += x.rows
+	}
+	r.data = make([]float64, r.rows*r.cols)
+	p := 0
+	for _, x := range ms {
+		x.RLock()
+		p += copy(r.data[p:], x.data)
+		x.RUnlock()
+	}
+	return r, nil
+}
+
+// HStackAll returns new matrix joined from given matrices side by side
+func HStackAll(ms ...*Matrix) (*Matrix, error) {
+	r := &Matrix{data: []float64{}}
+	if len(ms) == 0 {
+		return r, nil
+	}
+	r.rows = ms[0].rows
+	for k, x := range ms {
+		if x.rows != r.rows {
+			return nil, fmt.Errorf("Rows count %d of matrix %d is not equal to %d", x.rows, k, r.rows)
+		}
+		r.cols += x.cols
+	}
+	r.data = make([]float64, r.rows*r.cols)
+	p := 0
+	for _, x := range ms {
+		x.RLock()
+		for i := 0; i < x.rows; i++ {
+			copy(r.data[r.cols*i+p:], x.data[x.cols*i:x.cols*(i+1)])
+		}
+		x.RUnlock()
+		p += x.cols
+	}
+	return r, nil
+}
+
+// Resize returns new matrix with given dimentions keeping top-left elements of the matrix
+// and filling the rest with zeros
+func (m *Matrix) Resize(rows, cols int) (*Matrix, error) {
+	r, err := New(rows, cols)
+	if err != nil {
+		return nil, err
+	}
+	m.RLock()
+	defer m.RUnlock()
+	c := min(cols, m.cols)
+	for i := 0; i < min(rows, m.rows); i++ {
+		copy(r.data[cols*i:cols*i+c], m.data[m.cols*i:m.cols*i+c])
+	}
+	return r, nil
+}
+
+// Block returns new matrix assembled from the grid of blocks
+func Block(blocks [][]*Matrix) (*Matrix, error) {
+	r := &Matrix{data: []float64{}}
+	if len(blocks) == 0 {
+		return r, nil
+	}
+	heights := make([]int, len(blocks))
+	widths := make([]int, len(blocks[0]))
+	for bi, row := range blocks {
+		if len(row) != len(widths) {
+			return nil, fmt.Errorf("Block row %d has %d blocks, expected %d", bi, len(row), len(widths))
+		}
+		for bj, x := range row {
+			if bj == 0 {
+				heights[bi] = x.rows
+			}
+			if bi == 0 {
+				widths[bj] = x.cols
+			}
+			if x.rows != heights[bi] || x.cols != widths[bj] {
+				return nil, fmt.Errorf("Dimentions %dx%d of block (%d, %d) are not compatible with %dx%d", x.rows, x.cols, bi, bj, heights[bi], widths[bj])
+			}
+		}
+	}
+	for _, h := range heights {
+		r.rows += h
+	}
+	for _, w := range widths {
+		r.cols += w
+	}
+	r.data = make([]float64, r.rows*r.cols)
+	i0 := 0
+	for bi, row := range blocks {
+		j0 := 0
+		for bj, x := range row {
+			x.RLock()
+			for i := 0; i < x.rows; i++ {
+				copy(r.data[r.cols*(i0+i)+j0:], x.data[x.cols*i:x.cols*(i+1)])
+			}
+			x.RUnlock()
+			j0 += widths[bj]
+		}
+		i0 += heights[bi]
+	}
+	return r, nil
+}
+
+// Exp returns new matrix which is the exponential of the square matrix
+// calculated with scaling and squaring method with Padé approximant of degree 6
+func (m *Matrix) Exp() (*Matrix, error) {
+	if err := m.checkSquare(); err != nil {
+		return nil, err
+	}
+	const q = 6
+	_, e := math.Frexp(m.Norm(math.Inf(1)))
+	sc := max(0, e+1)
+	a := m.Clone()
+	a.Scale(math.Ldexp(1, -sc))
+	n, d := a.Clone(), a.Clone()
+	id, _ := Identity(m.rows)
+	// All matrices below are square of the same size,
+	// so sums and products can not fail and their errors are discarded
+	c := 0.5
+	n.Scale(c)
+	_ = n.Add(id)
+	d.Scale(-c)
+	_ = d.Add(id)
+	x := a
+	for k := 2; k <= q; k++ {
+		c *= float64(q-k+1) / float64(k*(2*q-k+1))
+		x, _ = a.Mul(x)
+		cx := x.Clone()
+		cx.Scale(c)
+		_ = n.Add(cx)
+		if k%2 == 0 {
+			_ = d.Add(cx)
+		} else {
+			_ = d.Sub(cx)
+		}
+	}
+	r, err := d.Solve(n)
+	if err != nil {
+		return nil, err
+	}
+	for k := 0; k < sc; k++ {
+		r, _ = r.Mul(r)
+	}
+	return r, nil
+}
+
+// NormalizeRows returns new matrix with every row of the matrix divided by its L2 norm,
+// rows with zero norm are left zero
+func (m *Matrix) NormalizeRows() *Matrix {
+	r := m.Clone()
+	for i := 0; i < r.rows; i++ {
+		row := r.data[r.cols*i : r.cols*(i+1)]
+		n := float64(0)
+		for _, v := range row {
+			n += v * v
+		}
+		if n == 0 {
+			continue
+		}
+		n = math.Sqrt(n)
+		for j := range row {
+			row[j] /= n
+		}
+	}
+	return r
+}
+
+// CosineSimilarity returns new matrix with cosine similarities between every row of the matrix
+// and every row of given one, similarity with zero row is 0
+func (m *Matrix) CosineSimilarity(x *Matrix) (*Matrix, error) {
+	if m.cols != x.cols {
+		return nil, fmt.Errorf("Columns count of two matrices %dx%d and %dx%d are not equal", m.rows, m.cols, x.rows, x.cols)
+	}
+	return m.NormalizeRows().Mul(x.NormalizeRows().T())
+}
+
+// PowerReal returns new symmetric matrix raised to real power p
+// calculated with eigendecomposition, fractional power requires
+// non-negative eigenvalues and negative power requires non-singular matrix
+func (m *Matrix) PowerReal(p float64) (*Matrix, error) {
+	values, q, err := m.EigenSymmetric()
+	if err != nil {
+		return nil, err
+	}
+	n := m.rows
+	norm := float64(0)
+	for _, v := range values {
+		norm = math.Max(norm, math.Abs(v))
+	}
+	for k, v := range values {
+		if math.Abs(v) <= eps*norm {
+			v = 0
+		}
+		if v < 0 && p != math.Trunc(p) {
+			return nil, fmt.Errorf("Eigenvalue %g is negative for fractional power %g", v, p)
+		}
+		if v == 0 && p < 0 {
+			return nil, ErrSingular
+		}
+		values[k] = math.Pow(v, p)
+	}
+	ql := q.Clone()
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			ql.data[n*i+j] *= values[j]
+		}
+	}
+	return ql.Mul(q.T())
+}
+
+// ElemPow raises every element in the matrix to power p,
+// negative elements raised to fractional power become NaN
+func (m *Matrix) ElemPow(p float64) {
+	m.Lock()
+	defer m.Unlock()
+	for k, v := range m.data {
+		m.data[k] = math.Pow(v, p)
+	}
+}
+
+// ElemExp replaces every element in the matrix with its exponential
+func (m *Matrix) ElemExp() {
+	m.Lock()
+	defer m.Unlock()
+	for k, v := range m.data {
+		m.data[k] = math.Exp(v)
+	}
+}
+
+// ElemLog replaces every element in the matrix with its natural logarithm,
+// the matrix is left untouched if any element is not positive
+func (m *Matrix) ElemLog() error {
+	m.Lock()
+	defer m.Unlock()
+	for k, v := range m.data {
+		if !(v > 0) {
+			return fmt.Errorf("Position (%d, %d) has value %g out of the domain of logarithm", k/m.cols, k%m.cols, v)
+		}
+	}
+	for k, v := range m.data {
+		m.data[k] = math.Log(v)
+	}
+	return nil
+}
+
+// ElemSqrt replaces every element in the matrix with its square root,
+// the matrix is left untouched if any element is negative
+func (m *Matrix) ElemSqrt() error {
+	m.Lock()
+	defer m.Unlock()
+	for k, v := range m.data {
+		if !(v >= 0) {
+			return fmt.Errorf("Position (%d, %d) has value %g out of the domain of square root", k/m.cols, k%m.cols, v)
+		}
+	}
+	for k, v := range m.data {
+		m.data[k] = math.Sqrt(v)
+	}
+	return nil
+}
+
+// ElemAbs replaces every element in the matrix with its absolute value
+func (m *Matrix) ElemAbs() {
+	m.Lock()
+	defer m.Unlock()
+	for k, v := range m.data {
+		m.data[k] = math.Abs(v)
+	}
+}
+
+// MaxRows returns new matrix with one column consisting of maximums of every row,
+// maximum of the row without elements is -Inf
+func (m *Matrix) MaxRows() *Matrix {
+	return m.reduceRows(math.Inf(-1), math.Max)
+}
+
+// MaxCols returns new matrix with one row consisting of maximums of every column,
+// maximum of the column without elements is -Inf
+func (m *Matrix) MaxCols() *Matrix {
+	return m.reduceCols(math.Inf(-1), math.Max)
+}
+
+// MinRows returns new matrix with one column consisting of minimums of every row,
+// minimum of the row without elements is +Inf
+func (m *Matrix) MinRows() *Matrix {
+	return m.reduceRows(math.Inf(1), math.Min)
+}
+
+// MinCols returns new matrix with one row consisting of minimums of every column,
+// minimum of the column without elements is +Inf
+func (m *Matrix) MinCols() *Matrix {
+	return m.reduceCols(math.Inf(1), math.Min)
+}
+
+func (m *Matrix) reduceRows(init float64, f func(a, b float64) float64) *Matrix {
+	r := &Matrix{
+		rows: m.rows,
+		cols: 1,
+		data: make([]float64, m.rows),
+	}
+	m.RLock()
+	defer m.RUnlock()
+	for i := 0; i < m.rows; i++ {
+		r.data[i] = init
+		for j := 0; j < m.cols; j++ {
+			r.data[i] = f(r.data[i], m.data[m.cols*i+j])
+		}
+	}
+	return r
+}
+
+func (m *Matrix) reduceCols(init float64, f func(a, b float64) float64) *Matrix {
+	r := &Matrix{
+		rows: 1,
+		cols: m.cols,
+		data: make([]float64, m.cols),
+	}
+	m.RLock()
+	defer m.RUnlock()
+	for j := 0; j < m.cols; j++ {
+		r.data[j] = init
+		for i := 0; i < m.rows; i++ {
+			r.data[j] = f(r.data[j], m.data[m.cols*i+j])
+		}
+	}
+	return r
+}
@@ -0,0 +1,2262 @@
+package matrix_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/andreipimenov/algebra/matrix"
+	"github.com/andreipimenov/algebra/matrix/matrixtest"
+)
+
+func fromSlice(t testing.TB, data [][]float64) *matrix.Matrix {
+	t.Helper()
+	m, err := matrix.FromSlice(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func newMatrix(t testing.TB, rows, cols int) *matrix.Matrix {
+	t.Helper()
+	m, err := matrix.New(rows, cols)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestCopyInto(t *testing.T) {
+	m := fromSlice(t, [][]float64{{1, 2}, {3, 4}})
+	dst := newMatrix(t, 2, 2)
+	if err := m.CopyInto(dst); err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, dst, m, 0)
+	dst.Set(0, 0, 5)
+	if v, _ := m.Get(0, 0); v != 1 {
+		t.Errorf("Source changed after copy: got %g, want 1", v)
+	}
+}
+
+func TestCopyIntoSame(t *testing.T) {
+	m := fromSlice(t, [][]float64{{1, 2}, {3, 4}})
+	if err := m.CopyInto(m); err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{1, 2}, {3, 4}}), 0)
+}
+
+func TestCopyIntoDimentions(t *testing.T) {
+	m := fromSlice(t, [][]float64{{1, 2}, {3, 4}})
+	if err := m.CopyInto(newMatrix(t, 2, 3)); err == nil {
+		t.Error("Expected error for mismatched dimentions")
+	}
+}
+
+func TestCopyIntoConcurrent(t *testing.T) {
+	a, b := newMatrix(t, 1000, 1000), newMatrix(t, 1000, 1000)
+	a.Fill(1)
+	b.Fill(2)
+	var wg sync.WaitGroup
+	for _, p := range [][2]*matrix.Matrix{{a, b}, {b, a}} {
+		wg.Add(1)
+		go func(src, dst *matrix.Matrix) {
+			defer wg.Done()
+			for k := 0; k < 50; k++ {
+				if err := src.CopyInto(dst); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}(p[0], p[1])
+	}
+	wg.Wait()
+}
+
+func TestForwardEliminate(t *testing.T) {
+	m := fromSlice(t, [][]float64{{0, 2, 1}, {1, 1, 1}, {4, 2, 5}})
+	u, swaps, err := m.ForwardEliminate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !u.IsUpperTriangular(1e-12) {
+		t.Errorf("Got not upper triangular matrix\n%v", u)
+	}
+	if len(swaps) != 3 || swaps[0] != 2 || swaps[1] != 2 || swaps[2] != 2 {
+		t.Errorf("Got swaps %v, want [2 2 2]", swaps)
+	}
+	det := float64(1)
+	for k, p := range swaps {
+		v, _ := u.Get(k, k)
+		det *= v
+		if p != k {
+			det = -det
+		}
+	}
+	if want, _ := m.Determinant(); math.Abs(det-want) > 1e-12 {
+		t.Errorf("Got determinant %g from elimination, want %g", det, want)
+	}
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{0, 2, 1}, {1, 1, 1}, {4, 2, 5}}), 0)
+}
+
+func TestForwardEliminateSingular(t *testing.T) {
+	m := fromSlice(t, [][]float64{{1, 2, 3}, {2, 4, 6}, {1, 1, 1}})
+	u, swaps, err := m.ForwardEliminate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !u.IsUpperTriangular(1e-12) {
+		t.Errorf("Got not upper triangular matrix\n%v", u)
+	}
+	if len(swaps) != m.Rank(1e-12) {
+		t.Errorf("Got %d swaps, want rank %d", len(swaps), m.Rank(1e-12))
+	}
+	if _, _, err := newMatrix(t, 2, 3).ForwardEliminate(); err == nil {
+		t.Error("Expected error for not square matrix")
+	}
+}
+
+func TestRREF(t *testing.T) {
+	m := fromSlice(t, [][]float64{{2, 1, -1, 8}, {-3, -1, 2, -11}, {-2, 1, 2, -3}})
+	r := m.RREF()
+	matrixtest.AssertEqual(t, r, fromSlice(t, [][]float64{{1, 0, 0, 2}, {0, 1, 0, 3}, {0, 0, 1, -1}}), 1e-12)
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			if v, _ := r.Get(i, j); i != j && v != 0 {
+				t.Errorf("Got %g at (%d, %d), want exact zero", v, i, j)
+			}
+		}
+	}
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{2, 1, -1, 8}, {-3, -1, 2, -11}, {-2, 1, 2, -3}}), 0)
+}
+
+func TestRREFRankDeficient(t *testing.T) {
+	m := fromSlice(t, [][]float64{{1, 2, 3}, {2, 4, 6}, {1, 1, 1}})
+	matrixtest.AssertEqual(t, m.RREF(), fromSlice(t, [][]float64{{1, 0, -1}, {0, 1, 2}, {0, 0, 0}}), 1e-12)
+	matrixtest.AssertEqual(t, newMatrix(t, 2, 3).RREF(), newMatrix(t, 2, 3), 0)
+}
+
+func TestFrobeniusInner(t *testing.T) {
+	a := fromSlice(t, [][]float64{{1, 2, 3}, {4, 5, 6}})
+	b := fromSlice(t, [][]float64{{-1, 0.5, 2}, {3, 0, -2}})
+	p, err := a.FrobeniusInner(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p != 6 {
+		t.Errorf("Got %g, want 6", p)
+	}
+	if d, _ := a.Dot(b); d != p {
+		t.Errorf("Got dot product %g, want %g", d, p)
+	}
+	if _, err := a.FrobeniusInner(a.T()); err == nil {
+		t.Error("Expected error for mismatched dimentions")
+	}
+}
+
+func TestMul(t *testing.T) {
+	a := fromSlice(t, [][]float64{{1, 2, 3}, {4, 5, 6}})
+	b := fromSlice(t, [][]float64{{7, 8}, {9, 10}, {11, 12}})
+	r, err := a.Mul(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, r, fromSlice(t, [][]float64{{58, 64}, {139, 154}}), 0)
+	r, err = b.Mul(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, r, fromSlice(t, [][]float64{{39, 54, 69}, {49, 68, 87}, {59, 82, 105}}), 0)
+}
+
+func TestMulOne(t *testing.T) {
+	r, err := fromSlice(t, [][]float64{{3}}).Mul(fromSlice(t, [][]float64{{-4}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, r, fromSlice(t, [][]float64{{-12}}), 0)
+}
+
+func TestMulDimentions(t *testing.T) {
+	a := fromSlice(t, [][]float64{{1, 2, 3}, {4, 5, 6}})
+	if _, err := a.Mul(a); err == nil {
+		t.Error("Expected error for incompatible dimentions")
+	}
+}
+
+func TestDeterminant(t *testing.T) {
+	tests := []struct {
+		name string
+		data [][]float64
+		want float64
+	}{
+		{"1x1", [][]float64{{-3}}, -3},
+		{"2x2", [][]float64{{4, 7}, {2, 6}}, 10},
+		{"3x3", [][]float64{{6, 1, 1}, {4, -2, 5}, {2, 8, 7}}, -306},
+		{"4x4", [][]float64{{0, 1, 0, 0}, {1, 0, 0, 0}, {0, 0, 2, 0}, {0, 0, 0, 3}}, -6},
+		{"5x5", [][]float64{{2, 0, 0, 0, 1}, {0, 3, 0, 0, 0}, {0, 0, 1, 4, 0}, {0, 0, 0, 1, 0}, {1, 0, 0, 0, 1}}, 3},
+		{"singular", [][]float64{{1, 2, 3, 4}, {2, 4, 6, 8}, {1, 0, 1, 0}, {0, 1, 0, 1}}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := fromSlice(t, tt.data).Determinant()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(d-tt.want) > 1e-9 {
+				t.Errorf("Got %g, want %g", d, tt.want)
+			}
+		})
+	}
+	if _, err := newMatrix(t, 2, 3).Determinant(); err == nil {
+		t.Error("Expected error for not square matrix")
+	}
+}
+
+func TestDeterminantNearSingular(t *testing.T) {
+	d, err := fromSlice(t, [][]float64{{1, 2, 3, 4}, {2, 4, 6, 8 + 1e-9}, {1, 0, 1, 0}, {0, 1, 0, 1}}).Determinant()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d == 0 || math.Abs(d) > 1e-8 {
+		t.Errorf("Got %g, want small non-zero determinant", d)
+	}
+}
+
+func TestInverse(t *testing.T) {
+	m := fromSlice(t, [][]float64{{0, 2, 1}, {1, 1, 1}, {4, 2, 5}})
+	inv, err := m.Inverse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, _ := m.Mul(inv)
+	id, _ := matrix.Identity(3)
+	matrixtest.AssertEqual(t, r, id, 1e-12)
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{0, 2, 1}, {1, 1, 1}, {4, 2, 5}}), 0)
+}
+
+func TestInverseErrors(t *testing.T) {
+	if _, err := newMatrix(t, 2, 3).Inverse(); err == nil {
+		t.Error("Expected error for not square matrix")
+	}
+	if _, err := fromSlice(t, [][]float64{{1, 2}, {2, 4}}).Inverse(); !errors.Is(err, matrix.ErrSingular) {
+		t.Errorf("Got error %v, want %v", err, matrix.ErrSingular)
+	}
+}
+
+func TestTrace(t *testing.T) {
+	if tr, err := fromSlice(t, [][]float64{{1, 2, 3}, {4, 5, 6}, {7, 8, -9}}).Trace(); err != nil || tr != -3 {
+		t.Errorf("Got %g (%v), want -3", tr, err)
+	}
+	if tr, err := fromSlice(t, [][]float64{{2.5}}).Trace(); err != nil || tr != 2.5 {
+		t.Errorf("Got %g (%v), want 2.5", tr, err)
+	}
+	if _, err := newMatrix(t, 2, 3).Trace(); err == nil {
+		t.Error("Expected error for not square matrix")
+	}
+}
+
+func TestFromSlice(t *testing.T) {
+	data := [][]float64{{1, 2, 3}, {4, 5, 6}}
+	m := fromSlice(t, data)
+	if rows, cols := m.Dimentions(); rows != 2 || cols != 3 {
+		t.Fatalf("Got dimentions %dx%d, want 2x3", rows, cols)
+	}
+	if v, _ := m.Get(1, 0); v != 4 {
+		t.Errorf("Got %g at (1, 0), want 4", v)
+	}
+	data[0][0] = 10
+	if v, _ := m.Get(0, 0); v != 1 {
+		t.Errorf("Got %g at (0, 0) after change of the slice, want 1", v)
+	}
+	if rows, cols := fromSlice(t, [][]float64{}).Dimentions(); rows != 0 || cols != 0 {
+		t.Errorf("Got dimentions %dx%d, want 0x0", rows, cols)
+	}
+}
+
+func TestFromSliceRagged(t *testing.T) {
+	_, err := matrix.FromSlice([][]float64{{1, 2}, {3, 4}, {5}})
+	if err == nil || !strings.Contains(err.Error(), "Row 2") {
+		t.Errorf("Got error %v, want error naming row 2", err)
+	}
+}
+
+func TestToSlice(t *testing.T) {
+	m := fromSlice(t, [][]float64{{1, 2}, {3, 4}})
+	s := m.ToSlice()
+	if len(s) != 2 || len(s[1]) != 2 || s[1][0] != 3 {
+		t.Fatalf("Got %v, want [[1 2] [3 4]]", s)
+	}
+	s[0][0] = 10
+	if v, _ := m.Get(0, 0); v != 1 {
+		t.Errorf("Got %g at (0, 0) after change of the slice, want 1", v)
+	}
+	if s := newMatrix(t, 0, 0).ToSlice(); s == nil || len(s) != 0 {
+		t.Errorf("Got %#v, want empty non-nil slice", s)
+	}
+}
+
+func TestCloneConcurrent(t *testing.T) {
+	m := newMatrix(t, 50, 50)
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for k := 0; k < 100; k++ {
+			m.Each(func(i, j int, v float64) float64 { return v + 1 })
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for k := 0; k < 100; k++ {
+			m.Clone()
+			m.T()
+		}
+	}()
+	wg.Wait()
+	if v, _ := m.Get(49, 49); v != 100 {
+		t.Errorf("Got %g, want 100", v)
+	}
+}
+
+func TestSumMaxMin(t *testing.T) {
+	m := fromSlice(t, [][]float64{{-5, 2}, {-1, -8}})
+	if s := m.Sum(); s != -12 {
+		t.Errorf("Got sum %g, want -12", s)
+	}
+	if v, err := m.Max(); err != nil || v != 2 {
+		t.Errorf("Got max %g (%v), want 2", v, err)
+	}
+	if v, err := m.Min(); err != nil || v != -8 {
+		t.Errorf("Got min %g (%v), want -8", v, err)
+	}
+	one := fromSlice(t, [][]float64{{-3}})
+	if v, _ := one.Max(); v != -3 {
+		t.Errorf("Got max %g of single element, want -3", v)
+	}
+	if v, _ := one.Min(); v != -3 {
+		t.Errorf("Got min %g of single element, want -3", v)
+	}
+	if s := one.Sum(); s != -3 {
+		t.Errorf("Got sum %g of single element, want -3", s)
+	}
+}
+
+func TestSumMaxMinEmpty(t *testing.T) {
+	m := newMatrix(t, 0, 0)
+	if s := m.Sum(); s != 0 {
+		t.Errorf("Got sum %g, want 0", s)
+	}
+	if _, err := m.Max(); !errors.Is(err, matrix.ErrEmpty) {
+		t.Errorf("Got error %v, want %v", err, matrix.ErrEmpty)
+	}
+	if _, err := m.Min(); !errors.Is(err, matrix.ErrEmpty) {
+		t.Errorf("Got error %v, want %v", err, matrix.ErrEmpty)
+	}
+}
+
+func TestSumRowsCols(t *testing.T) {
+	m := fromSlice(t, [][]float64{{1, 2}, {3, -4}, {5, 6}})
+	matrixtest.AssertEqual(t, m.SumRows(), fromSlice(t, [][]float64{{3}, {-1}, {11}}), 0)
+	matrixtest.AssertEqual(t, m.SumCols(), fromSlice(t, [][]float64{{9, 4}}), 0)
+}
+
+func TestGetRowCol(t *testing.T) {
+	m := fromSlice(t, [][]float64{{1, 2, 3}, {4, 5, 6}})
+	r, err := m.GetRow(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, r, fromSlice(t, [][]float64{{4, 5, 6}}), 0)
+	c, err := m.GetCol(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, c, fromSlice(t, [][]float64{{3}, {6}}), 0)
+	r.Set(0, 0, 10)
+	c.Set(0, 0, 10)
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{1, 2, 3}, {4, 5, 6}}), 0)
+}
+
+func TestGetRowColRange(t *testing.T) {
+	m := fromSlice(t, [][]float64{{1, 2, 3}, {4, 5, 6}})
+	for _, i := range []int{-1, 2} {
+		if _, err := m.GetRow(i); err == nil {
+			t.Errorf("Expected error for row %d", i)
+		}
+	}
+	for _, j := range []int{-1, 3} {
+		if _, err := m.GetCol(j); err == nil {
+			t.Errorf("Expected error for column %d", j)
+		}
+	}
+}
+
+func TestEquals(t *testing.T) {
+	a := fromSlice(t, [][]float64{{1, 2}, {3, 4}})
+	if !a.Equals(fromSlice(t, [][]float64{{1, 2}, {3, 4}})) {
+		t.Error("Expected equal matrices")
+	}
+	if a.Equals(fromSlice(t, [][]float64{{1, 2}, {3, 4 + 1e-15}})) {
+		t.Error("Expected not equal matrices")
+	}
+	if a.Equals(fromSlice(t, [][]float64{{1, 2, 3}, {4, 5, 6}})) {
+		t.Error("Expected not equal matrices of different dimentions")
+	}
+}
+
+func TestEqualsApprox(t *testing.T) {
+	a := fromSlice(t, [][]float64{{1, 2}, {3, 4}})
+	if !a.EqualsApprox(fromSlice(t, [][]float64{{1, 2}, {3, 4 + 1e-10}}), 1e-9) {
+		t.Error("Expected equal matrices within tolerance")
+	}
+	if a.EqualsApprox(fromSlice(t, [][]float64{{1, 2}, {3, 4.1}}), 1e-9) {
+		t.Error("Expected not equal matrices beyond tolerance")
+	}
+	if a.EqualsApprox(newMatrix(t, 1, 4), math.Inf(1)) {
+		t.Error("Expected not equal matrices of different dimentions")
+	}
+}
+
+func TestReshape(t *testing.T) {
+	m := fromSlice(t, [][]float64{{1, 2, 3}, {4, 5, 6}})
+	r, err := m.Reshape(3, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, r, fromSlice(t, [][]float64{{1, 2}, {3, 4}, {5, 6}}), 0)
+	r, err = m.Reshape(6, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, r, fromSlice(t, [][]float64{{1}, {2}, {3}, {4}, {5}, {6}}), 0)
+	r.Set(0, 0, 10)
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{1, 2, 3}, {4, 5, 6}}), 0)
+	for _, d := range [][2]int{{4, 2}, {-2, -3}} {
+		if _, err := m.Reshape(d[0], d[1]); err == nil {
+			t.Errorf("Expected error for dimentions %dx%d", d[0], d[1])
+		}
+	}
+}
+
+func TestHStack(t *testing.T) {
+	a := fromSlice(t, [][]float64{{1, 2}, {3, 4}})
+	b := fromSlice(t, [][]float64{{5, 6, 7}, {8, 9, 10}})
+	r, err := a.HStack(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, r, fromSlice(t, [][]float64{{1, 2, 5, 6, 7}, {3, 4, 8, 9, 10}}), 0)
+	matrixtest.AssertEqual(t, a, fromSlice(t, [][]float64{{1, 2}, {3, 4}}), 0)
+	if _, err := a.HStack(b.T()); err == nil {
+		t.Error("Expected error for different counts of rows")
+	}
+}
+
+func TestVStack(t *testing.T) {
+	a := fromSlice(t, [][]float64{{1, 2}, {3, 4}})
+	b := fromSlice(t, [][]float64{{5, 6}})
+	r, err := a.VStack(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, r, fromSlice(t, [][]float64{{1, 2}, {3, 4}, {5, 6}}), 0)
+	if _, err := a.VStack(b.T()); err == nil {
+		t.Error("Expected error for different counts of columns")
+	}
+}
+
+func TestSubmatrix(t *testing.T) {
+	m := fromSlice(t, [][]float64{{1, 2, 3, 4}, {5, 6, 7, 8}, {9, 10, 11, 12}, {13, 14, 15, 16}})
+	r, err := m.Submatrix(1, 1, 3, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, r, fromSlice(t, [][]float64{{6, 7}, {10, 11}}), 0)
+	r.Set(0, 0, 0)
+	if v, _ := m.Get(1, 1); v != 6 {
+		t.Errorf("Got %g at (1, 1) after change of submatrix, want 6", v)
+	}
+	for _, b := range [][4]int{{-1, 0, 2, 2}, {2, 0, 1, 2}, {0, 0, 5, 2}, {0, 3, 4, 2}} {
+		if _, err := m.Submatrix(b[0], b[1], b[2], b[3]); err == nil {
+			t.Errorf("Expected error for bounds %v", b)
+		}
+	}
+}
+
+func TestMap(t *testing.T) {
+	m := fromSlice(t, [][]float64{{1, 2}, {3, 4}})
+	r := m.Map(func(i, j int, v float64) float64 { return v*10 + float64(i-j) })
+	matrixtest.AssertEqual(t, r, fromSlice(t, [][]float64{{10, 19}, {31, 40}}), 0)
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{1, 2}, {3, 4}}), 0)
+}
+
+func TestParallelEach(t *testing.T) {
+	f := func(i, j int, v float64) float64 { return math.Sin(v) + float64(i*j) }
+	for _, d := range [][2]int{{0, 0}, {1, 5}, {3, 2}, {101, 37}} {
+		m, _ := matrix.Random(d[0], d[1], rand.New(rand.NewSource(1)))
+		want := m.Clone()
+		want.Each(f)
+		m.ParallelEach(f)
+		if !m.Equals(want) {
+			t.Errorf("Got different results of ParallelEach and Each for %dx%d", d[0], d[1])
+		}
+	}
+}
+
+func benchmarkEach(b *testing.B, each func(m *matrix.Matrix, f func(i, j int, v float64) float64)) {
+	m, _ := matrix.Random(2000, 2000, rand.New(rand.NewSource(1)))
+	f := func(i, j int, v float64) float64 { return math.Sqrt(v*v + 1) }
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		each(m, f)
+	}
+}
+
+func BenchmarkEach(b *testing.B) {
+	benchmarkEach(b, (*matrix.Matrix).Each)
+}
+
+func BenchmarkParallelEach(b *testing.B) {
+	benchmarkEach(b, (*matrix.Matrix).ParallelEach)
+}
+
+func TestNorm(t *testing.T) {
+	m := fromSlice(t, [][]float64{{1, -2}, {-3, 4}, {0, 5}})
+	if n := m.FrobeniusNorm(); math.Abs(n-math.Sqrt(55)) > 1e-12 {
+		t.Errorf("Got Frobenius norm %g, want %g", n, math.Sqrt(55))
+	}
+	tests := []struct {
+		p, want float64
+	}{
+		{1, 11},
+		{math.Inf(1), 7},
+		{2, math.Sqrt(55)},
+	}
+	for _, tt := range tests {
+		if n := m.Norm(tt.p); math.Abs(n-tt.want) > 1e-12 {
+			t.Errorf("Got %g norm %g, want %g", tt.p, n, tt.want)
+		}
+	}
+	if n := m.Norm(3); !math.IsNaN(n) {
+		t.Errorf("Got 3 norm %g, want NaN", n)
+	}
+}
+
+func TestLU(t *testing.T) {
+	m := fromSlice(t, [][]float64{{0, 2, 1, 3}, {1, 1, 1, 1}, {4, 2, 5, -1}, {2, -3, 0, 1}})
+	l, u, perm, err := m.LU()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !l.IsLowerTriangular(0) || !u.IsUpperTriangular(0) {
+		t.Errorf("Got factors which are not triangular\nl:\n%vu:\n%v", l, u)
+	}
+	for i, d := range l.Diag() {
+		if d != 1 {
+			t.Errorf("Got %g at (%d, %d) of l, want 1", d, i, i)
+		}
+	}
+	p := newMatrix(t, 4, 4)
+	for i, k := range perm {
+		p.Set(i, k, 1)
+	}
+	pa, _ := p.Mul(m)
+	lu, _ := l.Mul(u)
+	matrixtest.AssertEqual(t, lu, pa, 1e-12)
+}
+
+func TestLUErrors(t *testing.T) {
+	if _, _, _, err := newMatrix(t, 2, 3).LU(); err == nil {
+		t.Error("Expected error for not square matrix")
+	}
+	if _, _, _, err := fromSlice(t, [][]float64{{1, 2}, {2, 4}}).LU(); !errors.Is(err, matrix.ErrSingular) {
+		t.Errorf("Got error %v, want %v", err, matrix.ErrSingular)
+	}
+}
+
+func TestSolve(t *testing.T) {
+	a := fromSlice(t, [][]float64{{2, 1, -1}, {-3, -1, 2}, {-2, 1, 2}})
+	b := fromSlice(t, [][]float64{{8, 1}, {-11, 0}, {-3, 2}})
+	x, err := a.Solve(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := x.GetCol(0)
+	matrixtest.AssertEqual(t, got, fromSlice(t, [][]float64{{2}, {3}, {-1}}), 1e-12)
+	ax, _ := a.Mul(x)
+	matrixtest.AssertEqual(t, ax, b, 1e-12)
+}
+
+func TestSolveErrors(t *testing.T) {
+	a := fromSlice(t, [][]float64{{2, 1}, {4, 2}})
+	if _, err := a.Solve(newMatrix(t, 2, 1)); !errors.Is(err, matrix.ErrSingular) {
+		t.Errorf("Got error %v, want %v", err, matrix.ErrSingular)
+	}
+	if _, err := a.Solve(newMatrix(t, 3, 1)); err == nil {
+		t.Error("Expected error for incompatible dimentions")
+	}
+	if _, err := newMatrix(t, 2, 3).Solve(newMatrix(t, 2, 1)); err == nil {
+		t.Error("Expected error for not square matrix")
+	}
+}
+
+func TestDiagonal(t *testing.T) {
+	m := matrix.Diagonal([]float64{1, -2, 3})
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{1, 0, 0}, {0, -2, 0}, {0, 0, 3}}), 0)
+	if d := m.Diag(); !slices.Equal(d, []float64{1, -2, 3}) {
+		t.Errorf("Got diagonal %v, want [1 -2 3]", d)
+	}
+	if d := fromSlice(t, [][]float64{{1, 2, 3}, {4, 5, 6}}).Diag(); !slices.Equal(d, []float64{1, 5}) {
+		t.Errorf("Got diagonal %v, want [1 5]", d)
+	}
+	if d := fromSlice(t, [][]float64{{1, 2}, {3, 4}, {5, 6}}).Diag(); !slices.Equal(d, []float64{1, 4}) {
+		t.Errorf("Got diagonal %v, want [1 4]", d)
+	}
+}
+
+func TestRandom(t *testing.T) {
+	a, err := matrix.Random(3, 4, rand.New(rand.NewSource(42)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := matrix.Random(3, 4, rand.New(rand.NewSource(42)))
+	if !a.Equals(b) {
+		t.Error("Expected equal matrices from sources with the same seed")
+	}
+	for _, v := range a.View() {
+		for _, x := range v {
+			if x < 0 || x >= 1 {
+				t.Errorf("Got %g out of [0, 1)", x)
+			}
+		}
+	}
+	if _, err := matrix.Random(2, 2, nil); err != nil {
+		t.Error(err)
+	}
+	if _, err := matrix.Random(-1, 2, nil); err == nil {
+		t.Error("Expected error for negative dimentions")
+	}
+}
+
+func TestRandomRange(t *testing.T) {
+	m, err := matrix.RandomRange(10, 10, -5, -2, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lo, _ := m.Min(); lo < -5 {
+		t.Errorf("Got %g lower than -5", lo)
+	}
+	if hi, _ := m.Max(); hi >= -2 {
+		t.Errorf("Got %g not lower than -2", hi)
+	}
+}
+
+func TestKron(t *testing.T) {
+	a := fromSlice(t, [][]float64{{1, 2}, {3, 4}})
+	b := fromSlice(t, [][]float64{{0, 5}, {6, 7}})
+	matrixtest.AssertEqual(t, a.Kron(b), fromSlice(t, [][]float64{
+		{0, 5, 0, 10},
+		{6, 7, 12, 14},
+		{0, 15, 0, 20},
+		{18, 21, 24, 28},
+	}), 0)
+	if rows, cols := a.Kron(newMatrix(t, 3, 1)).Dimentions(); rows != 6 || cols != 2 {
+		t.Errorf("Got dimentions %dx%d, want 6x2", rows, cols)
+	}
+}
+
+func TestOuter(t *testing.T) {
+	r, err := fromSlice(t, [][]float64{{1}, {2}, {3}}).Outer(fromSlice(t, [][]float64{{4, 5}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := fromSlice(t, [][]float64{{4, 5}, {8, 10}, {12, 15}})
+	matrixtest.AssertEqual(t, r, want, 0)
+	r, _ = fromSlice(t, [][]float64{{1, 2, 3}}).Outer(fromSlice(t, [][]float64{{4}, {5}}))
+	matrixtest.AssertEqual(t, r, want, 0)
+	if _, err := newMatrix(t, 2, 2).Outer(newMatrix(t, 2, 1)); err == nil {
+		t.Error("Expected error for not vector")
+	}
+}
+
+func TestPower(t *testing.T) {
+	a := fromSlice(t, [][]float64{{1, 1}, {1, 0}})
+	r, err := a.Power(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a2, _ := a.Mul(a)
+	a3, _ := a2.Mul(a)
+	matrixtest.AssertEqual(t, r, a3, 0)
+	r, _ = a.Power(10)
+	matrixtest.AssertEqual(t, r, fromSlice(t, [][]float64{{89, 55}, {55, 34}}), 0)
+	r, _ = a.Power(0)
+	id, _ := matrix.Identity(2)
+	matrixtest.AssertEqual(t, r, id, 0)
+	r, err = a.Power(-2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, _ := r.Mul(a2)
+	matrixtest.AssertEqual(t, p, id, 1e-12)
+}
+
+func TestPowerErrors(t *testing.T) {
+	if _, err := newMatrix(t, 2, 3).Power(2); err == nil {
+		t.Error("Expected error for not square matrix")
+	}
+	if _, err := newMatrix(t, 2, 2).Power(-1); !errors.Is(err, matrix.ErrSingular) {
+		t.Errorf("Got error %v, want %v", err, matrix.ErrSingular)
+	}
+}
+
+func TestFillZero(t *testing.T) {
+	m := newMatrix(t, 2, 3)
+	m.Fill(2.5)
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{2.5, 2.5, 2.5}, {2.5, 2.5, 2.5}}), 0)
+	m.Zero()
+	matrixtest.AssertEqual(t, m, newMatrix(t, 2, 3), 0)
+	e := newMatrix(t, 0, 0)
+	e.Fill(1)
+	e.Zero()
+	matrixtest.AssertEqual(t, e, newMatrix(t, 0, 0), 0)
+}
+
+func TestClamp(t *testing.T) {
+	m := fromSlice(t, [][]float64{{-5, 0.5, 7}, {math.Inf(-1), 1, math.Inf(1)}})
+	if err := m.Clamp(0, 1); err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{0, 0.5, 1}, {0, 1, 1}}), 0)
+	m = fromSlice(t, [][]float64{{-5, 0.5, 7}})
+	if err := m.Clamp(math.Inf(-1), math.Inf(1)); err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{-5, 0.5, 7}}), 0)
+	if err := m.Clamp(1, 0); err == nil {
+		t.Error("Expected error for lower bound greater than upper bound")
+	}
+}
+
+func TestClampNaN(t *testing.T) {
+	m := fromSlice(t, [][]float64{{math.NaN(), 5}})
+	if err := m.Clamp(0, 1); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := m.Get(0, 0); !math.IsNaN(v) {
+		t.Errorf("Got %g, want NaN left untouched", v)
+	}
+	if v, _ := m.Get(0, 1); v != 1 {
+		t.Errorf("Got %g, want 1", v)
+	}
+}
+
+func TestHasNaNInf(t *testing.T) {
+	tests := []struct {
+		name               string
+		v                  float64
+		nan, inf, isFinite bool
+	}{
+		{"finite", 1, false, false, true},
+		{"NaN", math.NaN(), true, false, false},
+		{"+Inf", math.Inf(1), false, true, false},
+		{"-Inf", math.Inf(-1), false, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := fromSlice(t, [][]float64{{1, 2}, {3, tt.v}})
+			if m.HasNaN() != tt.nan {
+				t.Errorf("Got HasNaN %v, want %v", m.HasNaN(), tt.nan)
+			}
+			if m.HasInf() != tt.inf {
+				t.Errorf("Got HasInf %v, want %v", m.HasInf(), tt.inf)
+			}
+			if m.IsFinite() != tt.isFinite {
+				t.Errorf("Got IsFinite %v, want %v", m.IsFinite(), tt.isFinite)
+			}
+		})
+	}
+}
+
+func TestRank(t *testing.T) {
+	tests := []struct {
+		name string
+		m    *matrix.Matrix
+		want int
+	}{
+		{"zero", newMatrix(t, 3, 3), 0},
+		{"full", fromSlice(t, [][]float64{{0, 2, 1}, {1, 1, 1}, {4, 2, 5}}), 3},
+		{"deficient", fromSlice(t, [][]float64{{1, 2, 3}, {2, 4, 6}, {1, 1, 1}}), 2},
+		{"one", fromSlice(t, [][]float64{{1, 2, 3}, {2, 4, 6}, {-1, -2, -3}}), 1},
+		{"rectangular", fromSlice(t, [][]float64{{1, 2, 3, 4}, {2, 4, 6, 9}}), 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.m.Clone()
+			if r := tt.m.Rank(1e-12); r != tt.want {
+				t.Errorf("Got rank %d, want %d", r, tt.want)
+			}
+			if !tt.m.Equals(c) {
+				t.Error("Matrix changed after calculation of the rank")
+			}
+		})
+	}
+}
+
+func TestAll(t *testing.T) {
+	m := fromSlice(t, [][]float64{{1, 2, 3}, {4, 5, 6}})
+	s := m.ToSlice()
+	k := 0
+	for pos, v := range m.All() {
+		if want := [2]int{k / 3, k % 3}; pos != want {
+			t.Errorf("Got position %v, want %v", pos, want)
+		}
+		if v != s[pos[0]][pos[1]] {
+			t.Errorf("Got %g at %v, want %g", v, pos, s[pos[0]][pos[1]])
+		}
+		k++
+	}
+	if k != 6 {
+		t.Errorf("Got %d elements, want 6", k)
+	}
+	k = 0
+	for range m.All() {
+		k++
+		if k == 2 {
+			break
+		}
+	}
+	if k != 2 {
+		t.Errorf("Got %d elements before break, want 2", k)
+	}
+}
+
+func TestApplyRowCol(t *testing.T) {
+	m := fromSlice(t, [][]float64{{1, 2, 3}, {4, 5, 6}})
+	if err := m.ApplyRow(1, func(j int, v float64) float64 { return 2 * v }); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.ApplyCol(0, func(i int, v float64) float64 { return 0 }); err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{0, 2, 3}, {0, 10, 12}}), 0)
+	if err := m.ApplyRow(2, func(j int, v float64) float64 { return v }); err == nil {
+		t.Error("Expected error for out of range row")
+	}
+	if err := m.ApplyCol(-1, func(i int, v float64) float64 { return v }); err == nil {
+		t.Error("Expected error for out of range column")
+	}
+}
+
+func TestAddRow(t *testing.T) {
+	m := newMatrix(t, 4, 3)
+	m.Fill(1)
+	if err := m.AddRow(fromSlice(t, [][]float64{{1, -1, 0.5}})); err != nil {
+		t.Fatal(err)
+	}
+	want := fromSlice(t, [][]float64{{2, 0, 1.5}, {2, 0, 1.5}, {2, 0, 1.5}, {2, 0, 1.5}})
+	matrixtest.AssertEqual(t, m, want, 0)
+	if err := m.AddRow(fromSlice(t, [][]float64{{1, 2}})); err == nil {
+		t.Error("Expected error for incompatible dimentions")
+	}
+	if err := m.AddRow(fromSlice(t, [][]float64{{1}, {2}, {3}})); err == nil {
+		t.Error("Expected error for column vector")
+	}
+}
+
+func TestAddCol(t *testing.T) {
+	m := newMatrix(t, 2, 3)
+	if err := m.AddCol(fromSlice(t, [][]float64{{1}, {-2}})); err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{1, 1, 1}, {-2, -2, -2}}), 0)
+	if err := m.AddCol(fromSlice(t, [][]float64{{1, 2}})); err == nil {
+		t.Error("Expected error for row vector")
+	}
+}
+
+func TestMulParallel(t *testing.T) {
+	src := rand.New(rand.NewSource(1))
+	for _, d := range [][3]int{{1, 1, 1}, {3, 5, 2}, {70, 33, 129}, {130, 140, 150}} {
+		a, _ := matrix.Random(d[0], d[1], src)
+		b, _ := matrix.Random(d[1], d[2], src)
+		r, err := a.MulParallel(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, _ := a.Mul(b)
+		matrixtest.AssertEqual(t, r, want, 1e-12)
+	}
+	a := newMatrix(t, 2, 3)
+	if _, err := a.MulParallel(a); err == nil {
+		t.Error("Expected error for incompatible dimentions")
+	}
+}
+
+func benchmarkMul2048(b *testing.B, mul func(a, x *matrix.Matrix) (*matrix.Matrix, error)) {
+	src := rand.New(rand.NewSource(1))
+	a, _ := matrix.Random(2048, 2048, src)
+	x, _ := matrix.Random(2048, 2048, src)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		mul(a, x)
+	}
+}
+
+func BenchmarkMul2048(b *testing.B) {
+	benchmarkMul2048(b, (*matrix.Matrix).Mul)
+}
+
+func BenchmarkMulParallel2048(b *testing.B) {
+	benchmarkMul2048(b, (*matrix.Matrix).MulParallel)
+}
+
+func TestQR(t *testing.T) {
+	for _, m := range []*matrix.Matrix{
+		fromSlice(t, [][]float64{{12, -51, 4}, {6, 167, -68}, {-4, 24, -41}}),
+		fromSlice(t, [][]float64{{1, 2}, {3, -4}, {5, 6}, {-7, 8}}),
+		fromSlice(t, [][]float64{{1, 2}, {2, 4}, {3, 6}}),
+	} {
+		q, r, err := m.QR()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for pos, v := range r.All() {
+			if pos[0] > pos[1] && v != 0 {
+				t.Errorf("Got %g at %v below the diagonal of r", v, pos)
+			}
+		}
+		qr, _ := q.Mul(r)
+		matrixtest.AssertEqual(t, qr, m, 1e-12)
+		qtq, _ := q.T().Mul(q)
+		rows, _ := m.Dimentions()
+		id, _ := matrix.Identity(rows)
+		matrixtest.AssertEqual(t, qtq, id, 1e-12)
+	}
+	if _, _, err := newMatrix(t, 2, 3).QR(); err == nil {
+		t.Error("Expected error for matrix with more columns than rows")
+	}
+}
+
+func TestCholesky(t *testing.T) {
+	m := fromSlice(t, [][]float64{{4, 12, -16}, {12, 37, -43}, {-16, -43, 98}})
+	l, err := m.Cholesky()
+	if err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, l, fromSlice(t, [][]float64{{2, 0, 0}, {6, 1, 0}, {-8, 5, 3}}), 1e-12)
+	llt, _ := l.Mul(l.T())
+	matrixtest.AssertEqual(t, llt, m, 1e-12)
+}
+
+func TestCholeskyErrors(t *testing.T) {
+	if _, err := fromSlice(t, [][]float64{{4, 1}, {2, 3}}).Cholesky(); !errors.Is(err, matrix.ErrNotSymmetric) {
+		t.Errorf("Got error %v, want %v", err, matrix.ErrNotSymmetric)
+	}
+	if _, err := fromSlice(t, [][]float64{{1, 2}, {2, 1}}).Cholesky(); !errors.Is(err, matrix.ErrNotPositiveDefinite) {
+		t.Errorf("Got error %v, want %v", err, matrix.ErrNotPositiveDefinite)
+	}
+	if _, err := newMatrix(t, 2, 3).Cholesky(); err == nil {
+		t.Error("Expected error for not square matrix")
+	}
+}
+
+func TestLstSq(t *testing.T) {
+	xs := []float64{0, 1, 2, 3, 4}
+	ys := []float64{1.1, 2.9, 5.2, 6.8, 9.1}
+	a, b := newMatrix(t, 5, 2), newMatrix(t, 5, 1)
+	for i, x := range xs {
+		a.Set(i, 0, 1)
+		a.Set(i, 1, x)
+		b.Set(i, 0, ys[i])
+	}
+	x, err := a.LstSq(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, x, fromSlice(t, [][]float64{{1.04}, {1.99}}), 1e-12)
+}
+
+func TestLstSqExact(t *testing.T) {
+	a := fromSlice(t, [][]float64{{2, 1, -1}, {-3, -1, 2}, {-2, 1, 2}})
+	x, err := a.LstSq(fromSlice(t, [][]float64{{8}, {-11}, {-3}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, x, fromSlice(t, [][]float64{{2}, {3}, {-1}}), 1e-12)
+}
+
+func TestLstSqErrors(t *testing.T) {
+	a := fromSlice(t, [][]float64{{1, 2}, {2, 4}, {3, 6}})
+	if _, err := a.LstSq(newMatrix(t, 2, 1)); err == nil {
+		t.Error("Expected error for incompatible dimentions")
+	}
+	if _, err := a.LstSq(newMatrix(t, 3, 1)); !errors.Is(err, matrix.ErrSingular) {
+		t.Errorf("Got error %v, want %v", err, matrix.ErrSingular)
+	}
+}
+
+func TestIsSquare(t *testing.T) {
+	if !newMatrix(t, 2, 2).IsSquare() || !newMatrix(t, 0, 0).IsSquare() {
+		t.Error("Expected square matrix")
+	}
+	if newMatrix(t, 2, 3).IsSquare() {
+		t.Error("Expected not square matrix")
+	}
+}
+
+func TestIsSymmetric(t *testing.T) {
+	m := fromSlice(t, [][]float64{{1, 2, 3}, {2, 4, 5}, {3, 5 + 1e-10, 6}})
+	if !m.IsSymmetric(1e-9) {
+		t.Error("Expected symmetric matrix within tolerance")
+	}
+	if m.IsSymmetric(1e-12) {
+		t.Error("Expected not symmetric matrix beyond tolerance")
+	}
+	if newMatrix(t, 2, 3).IsSymmetric(1) {
+		t.Error("Expected not symmetric not square matrix")
+	}
+}
+
+func TestIsDiagonal(t *testing.T) {
+	if !matrix.Diagonal([]float64{1, 2, 3}).IsDiagonal(0) {
+		t.Error("Expected diagonal matrix")
+	}
+	m := fromSlice(t, [][]float64{{1, 1e-10}, {0, 2}})
+	if !m.IsDiagonal(1e-9) {
+		t.Error("Expected diagonal matrix within tolerance")
+	}
+	if m.IsDiagonal(0) {
+		t.Error("Expected not diagonal matrix")
+	}
+}
+
+func TestDeleteRowCol(t *testing.T) {
+	m := fromSlice(t, [][]float64{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}})
+	r, err := m.DeleteRow(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, r, fromSlice(t, [][]float64{{1, 2, 3}, {7, 8, 9}}), 0)
+	r, err = m.DeleteCol(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, r, fromSlice(t, [][]float64{{1, 2}, {4, 5}, {7, 8}}), 0)
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}}), 0)
+	if _, err := m.DeleteRow(3); err == nil {
+		t.Error("Expected error for out of range row")
+	}
+	if _, err := m.DeleteCol(-1); err == nil {
+		t.Error("Expected error for out of range column")
+	}
+}
+
+func TestInsertRow(t *testing.T) {
+	m := fromSlice(t, [][]float64{{1, 2}, {3, 4}})
+	tests := []struct {
+		i    int
+		want [][]float64
+	}{
+		{0, [][]float64{{0, 0}, {1, 2}, {3, 4}}},
+		{1, [][]float64{{1, 2}, {0, 0}, {3, 4}}},
+		{2, [][]float64{{1, 2}, {3, 4}, {0, 0}}},
+	}
+	for _, tt := range tests {
+		r, err := m.InsertRow(tt.i, []float64{0, 0})
+		if err != nil {
+			t.Fatal(err)
+		}
+		matrixtest.AssertEqual(t, r, fromSlice(t, tt.want), 0)
+	}
+	if _, err := m.InsertRow(3, []float64{0, 0}); err == nil {
+		t.Error("Expected error for out of range row")
+	}
+	if _, err := m.InsertRow(0, []float64{0}); err == nil {
+		t.Error("Expected error for wrong count of values")
+	}
+}
+
+func TestInsertCol(t *testing.T) {
+	m := fromSlice(t, [][]float64{{1, 2}, {3, 4}})
+	r, err := m.InsertCol(1, []float64{5, 6})
+	if err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, r, fromSlice(t, [][]float64{{1, 5, 2}, {3, 6, 4}}), 0)
+	r, _ = m.InsertCol(2, []float64{5, 6})
+	matrixtest.AssertEqual(t, r, fromSlice(t, [][]float64{{1, 2, 5}, {3, 4, 6}}), 0)
+	if _, err := m.InsertCol(-1, []float64{5, 6}); err == nil {
+		t.Error("Expected error for out of range column")
+	}
+	if _, err := m.InsertCol(0, []float64{5, 6, 7}); err == nil {
+		t.Error("Expected error for wrong count of values")
+	}
+}
+
+func TestFormat(t *testing.T) {
+	m := fromSlice(t, [][]float64{{0.001, 123456.5}, {-2, 3}})
+	tests := []struct {
+		precision, width int
+		want             string
+	}{
+		{2, 0, "0.00      123456.50 \n-2.00     3.00      \n"},
+		{1, 10, "0.0       123456.5  \n-2.0      3.0       \n"},
+		{4, -1, "0.0010      123456.5000 \n-2.0000     3.0000      \n"},
+	}
+	for _, tt := range tests {
+		if got := m.Format(tt.precision, tt.width); got != tt.want {
+			t.Errorf("Got Format(%d, %d)\n%q\nwant\n%q", tt.precision, tt.width, got, tt.want)
+		}
+	}
+	if got, want := m.String(), m.Format(3, 15); got != want {
+		t.Errorf("Got String\n%q\nwant\n%q", got, want)
+	}
+	if got := newMatrix(t, 0, 0).Format(3, 0); got != "" {
+		t.Errorf("Got %q for empty matrix, want empty string", got)
+	}
+}
+
+func assertEigen(t *testing.T, m *matrix.Matrix, values []float64, vectors *matrix.Matrix) {
+	t.Helper()
+	for j, lambda := range values {
+		v, _ := vectors.GetCol(j)
+		av, _ := m.Mul(v)
+		matrixtest.AssertEqual(t, av, v.Scaled(lambda), 1e-10)
+		if n := v.FrobeniusNorm(); math.Abs(n-1) > 1e-12 {
+			t.Errorf("Got eigenvector with norm %g, want 1", n)
+		}
+	}
+}
+
+func TestEigenSymmetric(t *testing.T) {
+	m := matrix.Diagonal([]float64{3, -1, 2})
+	values, vectors, err := m.EigenSymmetric()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(values, []float64{-1, 2, 3}) {
+		t.Errorf("Got eigenvalues %v, want [-1 2 3]", values)
+	}
+	assertEigen(t, m, values, vectors)
+
+	m = fromSlice(t, [][]float64{{2, 1}, {1, 2}})
+	values, vectors, err = m.EigenSymmetric()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(values) != 2 || math.Abs(values[0]-1) > 1e-12 || math.Abs(values[1]-3) > 1e-12 {
+		t.Errorf("Got eigenvalues %v, want [1 3]", values)
+	}
+	assertEigen(t, m, values, vectors)
+
+	m = fromSlice(t, [][]float64{{4, 1, -2, 2}, {1, 2, 0, 1}, {-2, 0, 3, -2}, {2, 1, -2, -1}})
+	values, vectors, _ = m.EigenSymmetric()
+	assertEigen(t, m, values, vectors)
+}
+
+func TestEigenSymmetricErrors(t *testing.T) {
+	if _, _, err := fromSlice(t, [][]float64{{1, 2}, {3, 4}}).EigenSymmetric(); !errors.Is(err, matrix.ErrNotSymmetric) {
+		t.Errorf("Got error %v, want %v", err, matrix.ErrNotSymmetric)
+	}
+}
+
+func TestViewConcurrent(t *testing.T) {
+	m := newMatrix(t, 20, 20)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for k := 1; k <= 100; k++ {
+			for i := 0; i < 20; i++ {
+				for j := 0; j < 20; j++ {
+					m.Set(i, j, float64(k))
+				}
+			}
+		}
+	}()
+	// Elements are written in row-major order with growing values,
+	// so every snapshot is non-increasing and spans at most two passes
+	for k := 0; k < 100; k++ {
+		v := m.View()
+		prev := v[0][0]
+		for _, row := range v {
+			for _, x := range row {
+				if x > prev || v[0][0]-x > 1 {
+					t.Fatalf("Got inconsistent snapshot with %g and %g", v[0][0], x)
+				}
+				prev = x
+			}
+		}
+	}
+	wg.Wait()
+	v := m.View()
+	v[0][0] = -1
+	if x, _ := m.Get(0, 0); x != 100 {
+		t.Errorf("Got %g after change of the snapshot, want 100", x)
+	}
+}
+
+func TestTransposeInPlace(t *testing.T) {
+	m := fromSlice(t, [][]float64{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}})
+	want := m.T()
+	if err := m.TransposeInPlace(); err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, m, want, 0)
+	if err := newMatrix(t, 2, 3).TransposeInPlace(); err == nil {
+		t.Error("Expected error for not square matrix")
+	}
+}
+
+func TestArgmaxArgmin(t *testing.T) {
+	tests := []struct {
+		name                   string
+		data                   [][]float64
+		maxI, maxJ, minI, minJ int
+	}{
+		{"mixed", [][]float64{{1, 5, -2}, {0, 3, 4}}, 0, 1, 0, 2},
+		{"ties", [][]float64{{7, 1, 7}, {1, 7, 1}}, 0, 0, 0, 1},
+		{"negative", [][]float64{{-3, -1}, {-8, -2}}, 0, 1, 1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := fromSlice(t, tt.data)
+			if i, j, err := m.Argmax(); err != nil || i != tt.maxI || j != tt.maxJ {
+				t.Errorf("Got argmax (%d, %d) (%v), want (%d, %d)", i, j, err, tt.maxI, tt.maxJ)
+			}
+			if i, j, err := m.Argmin(); err != nil || i != tt.minI || j != tt.minJ {
+				t.Errorf("Got argmin (%d, %d) (%v), want (%d, %d)", i, j, err, tt.minI, tt.minJ)
+			}
+		})
+	}
+	if _, _, err := newMatrix(t, 0, 3).Argmax(); !errors.Is(err, matrix.ErrEmpty) {
+		t.Errorf("Got error %v, want %v", err, matrix.ErrEmpty)
+	}
+	if _, _, err := newMatrix(t, 0, 3).Argmin(); !errors.Is(err, matrix.ErrEmpty) {
+		t.Errorf("Got error %v, want %v", err, matrix.ErrEmpty)
+	}
+}
+
+func TestSoftmaxRows(t *testing.T) {
+	m := fromSlice(t, [][]float64{{0, math.Log(3)}, {1000, 1000}, {-1, 0}})
+	r := m.SoftmaxRows()
+	e := math.E
+	matrixtest.AssertEqual(t, r, fromSlice(t, [][]float64{{0.25, 0.75}, {0.5, 0.5}, {1 / (1 + e), e / (1 + e)}}), 1e-12)
+	sums := r.SumRows()
+	for i := 0; i < 3; i++ {
+		if s, _ := sums.Get(i, 0); math.Abs(s-1) > 1e-12 {
+			t.Errorf("Got sum %g of row %d, want 1", s, i)
+		}
+	}
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{0, math.Log(3)}, {1000, 1000}, {-1, 0}}), 0)
+}
+
+func TestCond(t *testing.T) {
+	id, _ := matrix.Identity(4)
+	tests := []struct {
+		name string
+		m    *matrix.Matrix
+		want float64
+	}{
+		{"identity", id, 1},
+		{"diagonal", matrix.Diagonal([]float64{1, -4, 2}), 4},
+		{"symmetric", fromSlice(t, [][]float64{{2, 1}, {1, 2}}), 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := tt.m.Cond()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(c-tt.want) > 1e-12*tt.want {
+				t.Errorf("Got %g, want %g", c, tt.want)
+			}
+		})
+	}
+	c, err := fromSlice(t, [][]float64{{1, 1}, {1, 1 + 1e-8}}).Cond()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c < 1e8 {
+		t.Errorf("Got %g for ill-conditioned matrix, want at least 1e8", c)
+	}
+}
+
+func TestCondErrors(t *testing.T) {
+	if _, err := newMatrix(t, 2, 3).Cond(); err == nil {
+		t.Error("Expected error for not square matrix")
+	}
+	if _, err := fromSlice(t, [][]float64{{1, 2}, {2, 4}}).Cond(); !errors.Is(err, matrix.ErrSingular) {
+		t.Errorf("Got error %v, want %v", err, matrix.ErrSingular)
+	}
+}
+
+func TestNormalizeFrobenius(t *testing.T) {
+	m := fromSlice(t, [][]float64{{3, 0}, {0, -4}})
+	if err := m.NormalizeFrobenius(); err != nil {
+		t.Fatal(err)
+	}
+	if n := m.FrobeniusNorm(); math.Abs(n-1) > 1e-15 {
+		t.Errorf("Got norm %g, want 1", n)
+	}
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{0.6, 0}, {0, -0.8}}), 1e-15)
+	if err := newMatrix(t, 2, 2).NormalizeFrobenius(); err == nil {
+		t.Error("Expected error for zero norm")
+	}
+}
+
+func TestNormalizeTrace(t *testing.T) {
+	m := fromSlice(t, [][]float64{{3, 1}, {2, 5}})
+	if err := m.NormalizeTrace(); err != nil {
+		t.Fatal(err)
+	}
+	if tr, _ := m.Trace(); math.Abs(tr-1) > 1e-15 {
+		t.Errorf("Got trace %g, want 1", tr)
+	}
+	if err := fromSlice(t, [][]float64{{1, 2}, {3, -1}}).NormalizeTrace(); err == nil {
+		t.Error("Expected error for zero trace")
+	}
+	if err := newMatrix(t, 2, 3).NormalizeTrace(); err == nil {
+		t.Error("Expected error for not square matrix")
+	}
+}
+
+func TestEachCtx(t *testing.T) {
+	m := fromSlice(t, [][]float64{{1, 2}, {3, 4}, {5, 6}})
+	err := m.EachCtx(context.Background(), func(i, j int, v float64) (float64, error) {
+		return v * 2, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{2, 4}, {6, 8}, {10, 12}}), 0)
+}
+
+func TestEachCtxCancel(t *testing.T) {
+	m := newMatrix(t, 100, 100)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var calls atomic.Int64
+	err := m.EachCtx(ctx, func(i, j int, v float64) (float64, error) {
+		if calls.Add(1) == 10 {
+			cancel()
+		}
+		return 1, nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Got error %v, want %v", err, context.Canceled)
+	}
+	if n := calls.Load(); n >= 100*100 {
+		t.Errorf("Got %d calls, want iteration stopped after cancellation", n)
+	}
+	if s := m.Sum(); s == 100*100 {
+		t.Error("Got every element processed after cancellation")
+	}
+
+	calls.Store(0)
+	if err := m.EachCtx(ctx, func(i, j int, v float64) (float64, error) {
+		calls.Add(1)
+		return v, nil
+	}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Got error %v for cancelled context, want %v", err, context.Canceled)
+	}
+	if n := calls.Load(); n != 0 {
+		t.Errorf("Got %d calls for cancelled context, want 0", n)
+	}
+}
+
+func TestEachCtxError(t *testing.T) {
+	m := newMatrix(t, 100, 100)
+	fail := errors.New("fail")
+	var calls atomic.Int64
+	err := m.EachCtx(context.Background(), func(i, j int, v float64) (float64, error) {
+		calls.Add(1)
+		if i == 5 && j == 5 {
+			return 0, fail
+		}
+		return v, nil
+	})
+	if !errors.Is(err, fail) {
+		t.Errorf("Got error %v, want %v", err, fail)
+	}
+	if n := calls.Load(); n >= 100*100 {
+		t.Errorf("Got %d calls, want iteration stopped after error", n)
+	}
+}
+
+func TestTraceMul(t *testing.T) {
+	a := fromSlice(t, [][]float64{{1, 2, 3}, {4, 5, 6}})
+	b := fromSlice(t, [][]float64{{7, -8}, {9, 10}, {-11, 12}})
+	got, err := a.TraceMul(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ab, _ := a.Mul(b)
+	want, _ := ab.Trace()
+	if got != want {
+		t.Errorf("Got %g, want %g", got, want)
+	}
+	if _, err := a.TraceMul(a); err == nil {
+		t.Error("Expected error for incompatible dimentions")
+	}
+	if _, err := a.TraceMul(newMatrix(t, 3, 3)); err == nil {
+		t.Error("Expected error for not square product")
+	}
+}
+
+func TestMasks(t *testing.T) {
+	m := fromSlice(t, [][]float64{{-2, 0, 1}, {3, -0.5, 1}})
+	matrixtest.AssertEqual(t, m.Greater(0), fromSlice(t, [][]float64{{0, 0, 1}, {1, 0, 1}}), 0)
+	matrixtest.AssertEqual(t, m.Less(0), fromSlice(t, [][]float64{{1, 0, 0}, {0, 1, 0}}), 0)
+	matrixtest.AssertEqual(t, m.EqualScalar(1), fromSlice(t, [][]float64{{0, 0, 1}, {0, 0, 1}}), 0)
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{-2, 0, 1}, {3, -0.5, 1}}), 0)
+}
+
+func TestWhere(t *testing.T) {
+	m := fromSlice(t, [][]float64{{-2, 0, 1}, {3, -0.5, 1}})
+	if err := m.Where(fromSlice(t, [][]float64{{1, 0, 0}, {0, 1, 0}}), 9); err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{9, 0, 1}, {3, 9, 1}}), 0)
+	if err := m.Where(m.Less(0), 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Where(newMatrix(t, 3, 2), 0); err == nil {
+		t.Error("Expected error for mismatched dimentions")
+	}
+}
+
+func TestAdjugate(t *testing.T) {
+	m := fromSlice(t, [][]float64{{-3, 2, -5}, {-1, 0, -2}, {3, -4, 1}})
+	adj, err := m.Adjugate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, adj, fromSlice(t, [][]float64{{-8, 18, -4}, {-5, 12, -1}, {4, -6, 2}}), 1e-12)
+	det, _ := m.Determinant()
+	inv, _ := m.Inverse()
+	matrixtest.AssertEqual(t, adj.Scaled(1/det), inv, 1e-12)
+	adj, _ = fromSlice(t, [][]float64{{5}}).Adjugate()
+	matrixtest.AssertEqual(t, adj, fromSlice(t, [][]float64{{1}}), 0)
+	if _, err := newMatrix(t, 2, 3).Adjugate(); err == nil {
+		t.Error("Expected error for not square matrix")
+	}
+}
+
+func TestStringLabeled(t *testing.T) {
+	m := fromSlice(t, [][]float64{{1, -2}, {3, 40}})
+	s, err := m.StringLabeled([]string{"a", "bb"}, []string{"x", "y"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "   x     y     \n" +
+		"a  1.000 -2.000\n" +
+		"bb 3.000 40.000\n"
+	if s != want {
+		t.Errorf("Got\n%q\nwant\n%q", s, want)
+	}
+	s, err = m.StringLabeled(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(s, "  0     1     \n0 ") {
+		t.Errorf("Got %q without numeric labels", s)
+	}
+	if _, err := m.StringLabeled([]string{"a"}, nil); err == nil {
+		t.Error("Expected error for mismatched count of labels")
+	}
+}
+
+func TestStackAll(t *testing.T) {
+	rows := make([]*matrix.Matrix, 5)
+	data := make([][]float64, 5)
+	for k := range rows {
+		data[k] = []float64{float64(k), float64(k * 10), float64(k * 100)}
+		rows[k] = fromSlice(t, [][]float64{data[k]})
+	}
+	v, err := matrix.VStackAll(rows...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, v, fromSlice(t, data), 0)
+	h, err := matrix.HStackAll(v.T(), v.T())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := v.T().HStack(v.T())
+	matrixtest.AssertEqual(t, h, want, 0)
+	for _, f := range []func(...*matrix.Matrix) (*matrix.Matrix, error){matrix.VStackAll, matrix.HStackAll} {
+		r, err := f()
+		if err != nil {
+			t.Fatal(err)
+		}
+		matrixtest.AssertEqual(t, r, newMatrix(t, 0, 0), 0)
+	}
+	if _, err := matrix.VStackAll(v, v.T()); err == nil {
+		t.Error("Expected error for mismatched columns count")
+	}
+	if _, err := matrix.HStackAll(v, v.T()); err == nil {
+		t.Error("Expected error for mismatched rows count")
+	}
+}
+
+func TestResize(t *testing.T) {
+	m := fromSlice(t, [][]float64{{1, 2, 3}, {4, 5, 6}})
+	tests := []struct {
+		name       string
+		rows, cols int
+		want       [][]float64
+	}{
+		{"grow", 3, 4, [][]float64{{1, 2, 3, 0}, {4, 5, 6, 0}, {0, 0, 0, 0}}},
+		{"shrink", 1, 2, [][]float64{{1, 2}}},
+		{"mixed", 3, 1, [][]float64{{1}, {4}, {0}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := m.Resize(tt.rows, tt.cols)
+			if err != nil {
+				t.Fatal(err)
+			}
+			matrixtest.AssertEqual(t, r, fromSlice(t, tt.want), 0)
+		})
+	}
+	r, err := m.Resize(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, r, newMatrix(t, 0, 0), 0)
+	if _, err := m.Resize(-1, 2); err == nil {
+		t.Error("Expected error for negative dimentions")
+	}
+}
+
+func TestBlock(t *testing.T) {
+	a := fromSlice(t, [][]float64{{1, 2}, {3, 4}})
+	b := fromSlice(t, [][]float64{{5}, {6}})
+	c := fromSlice(t, [][]float64{{7, 8}})
+	d := fromSlice(t, [][]float64{{9}})
+	r, err := matrix.Block([][]*matrix.Matrix{{a, b}, {c, d}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, r, fromSlice(t, [][]float64{{1, 2, 5}, {3, 4, 6}, {7, 8, 9}}), 0)
+	r, err = matrix.Block(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, r, newMatrix(t, 0, 0), 0)
+	if _, err := matrix.Block([][]*matrix.Matrix{{a, b}, {c}}); err == nil {
+		t.Error("Expected error for ragged grid of blocks")
+	}
+	if _, err := matrix.Block([][]*matrix.Matrix{{a, c}, {c, d}}); err == nil {
+		t.Error("Expected error for incompatible blocks")
+	}
+}
+
+func TestNormalizeRows(t *testing.T) {
+	m := fromSlice(t, [][]float64{{3, 4}, {0, 0}, {-2, 0}})
+	matrixtest.AssertEqual(t, m.NormalizeRows(), fromSlice(t, [][]float64{{0.6, 0.8}, {0, 0}, {-1, 0}}), 1e-15)
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{3, 4}, {0, 0}, {-2, 0}}), 0)
+}
+
+func TestCosineSimilarity(t *testing.T) {
+	a := fromSlice(t, [][]float64{{1, 0}, {1, 1}, {0, 0}})
+	b := fromSlice(t, [][]float64{{2, 0}, {0, -3}})
+	r, err := a.CosineSimilarity(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, r, fromSlice(t, [][]float64{{1, 0}, {math.Sqrt2 / 2, -math.Sqrt2 / 2}, {0, 0}}), 1e-15)
+	if _, err := a.CosineSimilarity(a.T()); err == nil {
+		t.Error("Expected error for mismatched columns count")
+	}
+}
+
+func TestMust(t *testing.T) {
+	m := newMatrix(t, 2, 2)
+	m.MustSet(1, 0, 5)
+	if v := m.MustGet(1, 0); v != 5 {
+		t.Errorf("Got %g, want 5", v)
+	}
+	for _, f := range []func(){
+		func() { m.MustGet(2, 0) },
+		func() { m.MustSet(0, -1, 1) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("Expected panic for out of range position")
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func TestSetBatch(t *testing.T) {
+	m := newMatrix(t, 2, 2)
+	if err := m.SetBatch([]matrix.Entry{{I: 0, J: 1, V: 2}, {I: 1, J: 0, V: 3}}); err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{0, 2}, {3, 0}}), 0)
+	err := m.SetBatch([]matrix.Entry{{I: 0, J: 0, V: 1}, {I: 2, J: 0, V: 1}})
+	if err == nil || !strings.Contains(err.Error(), "Entry 1") {
+		t.Errorf("Got error %v, want error naming entry 1", err)
+	}
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{0, 2}, {3, 0}}), 0)
+}
+
+func TestPowerReal(t *testing.T) {
+	a := fromSlice(t, [][]float64{{4, 1, 0}, {1, 3, 1}, {0, 1, 2}})
+	s, err := a.PowerReal(0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ss, _ := s.Mul(s)
+	matrixtest.AssertEqual(t, ss, a, 1e-12)
+	p, err := a.PowerReal(-1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inv, _ := a.Inverse()
+	matrixtest.AssertEqual(t, p, inv, 1e-12)
+	if _, err := fromSlice(t, [][]float64{{1, 0}, {0, -1}}).PowerReal(0.5); err == nil {
+		t.Error("Expected error for negative eigenvalue")
+	}
+	if _, err := fromSlice(t, [][]float64{{1, 0}, {0, 0}}).PowerReal(-1); !errors.Is(err, matrix.ErrSingular) {
+		t.Errorf("Got error %v, want %v", err, matrix.ErrSingular)
+	}
+	if _, err := fromSlice(t, [][]float64{{1, 2}, {0, 1}}).PowerReal(2); !errors.Is(err, matrix.ErrNotSymmetric) {
+		t.Errorf("Got error %v, want %v", err, matrix.ErrNotSymmetric)
+	}
+}
+
+func TestLDL(t *testing.T) {
+	a := fromSlice(t, [][]float64{{4, 2, -2}, {2, -1, 3}, {-2, 3, 5}})
+	l, d, err := a.LDL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !l.IsLowerTriangular(0) || !d.IsDiagonal(0) {
+		t.Error("Expected lower triangular l and diagonal d")
+	}
+	for i := 0; i < 3; i++ {
+		if v, _ := l.Get(i, i); v != 1 {
+			t.Errorf("Got %g on diagonal of l, want 1", v)
+		}
+	}
+	ld, _ := l.Mul(d)
+	r, _ := ld.Mul(l.T())
+	matrixtest.AssertEqual(t, r, a, 1e-12)
+	if _, _, err := fromSlice(t, [][]float64{{1, 2}, {3, 4}}).LDL(); !errors.Is(err, matrix.ErrNotSymmetric) {
+		t.Errorf("Got error %v, want %v", err, matrix.ErrNotSymmetric)
+	}
+	if _, _, err := fromSlice(t, [][]float64{{0, 1}, {1, 0}}).LDL(); !errors.Is(err, matrix.ErrSingular) {
+		t.Errorf("Got error %v, want %v", err, matrix.ErrSingular)
+	}
+}
+
+func TestElemPow(t *testing.T) {
+	m := fromSlice(t, [][]float64{{2, -3}, {4, 0}})
+	m.ElemPow(2)
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{4, 9}, {16, 0}}), 0)
+	m.ElemPow(0.5)
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{2, 3}, {4, 0}}), 0)
+	m = fromSlice(t, [][]float64{{-4}})
+	m.ElemPow(0.5)
+	if v, _ := m.Get(0, 0); !math.IsNaN(v) {
+		t.Errorf("Got %g for negative element to fractional power, want NaN", v)
+	}
+}
+
+func TestElemFunctions(t *testing.T) {
+	m := fromSlice(t, [][]float64{{0, 1}, {-2, 0.5}})
+	m.ElemExp()
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{1, math.E}, {math.Exp(-2), math.Exp(0.5)}}), 0)
+	if err := m.ElemLog(); err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{0, 1}, {-2, 0.5}}), 1e-15)
+	m.ElemAbs()
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{0, 1}, {2, 0.5}}), 1e-15)
+	m = fromSlice(t, [][]float64{{4, 0.25}, {0, 9}})
+	if err := m.ElemSqrt(); err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{2, 0.5}, {0, 3}}), 0)
+}
+
+func TestElemFunctionsDomain(t *testing.T) {
+	m := fromSlice(t, [][]float64{{1, 0}, {-1, 4}})
+	if err := m.ElemLog(); err == nil {
+		t.Error("Expected error for logarithm of zero")
+	}
+	if err := m.ElemSqrt(); err == nil {
+		t.Error("Expected error for square root of negative value")
+	}
+	if err := fromSlice(t, [][]float64{{math.NaN()}}).ElemSqrt(); err == nil {
+		t.Error("Expected error for square root of NaN")
+	}
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{1, 0}, {-1, 4}}), 0)
+}
+
+func TestMaxMinRowsCols(t *testing.T) {
+	m := fromSlice(t, [][]float64{{1, -5, 3}, {-2, 4, 0}})
+	matrixtest.AssertEqual(t, m.MaxRows(), fromSlice(t, [][]float64{{3}, {4}}), 0)
+	matrixtest.AssertEqual(t, m.MaxCols(), fromSlice(t, [][]float64{{1, 4, 3}}), 0)
+	matrixtest.AssertEqual(t, m.MinRows(), fromSlice(t, [][]float64{{-5}, {-2}}), 0)
+	matrixtest.AssertEqual(t, m.MinCols(), fromSlice(t, [][]float64{{-2, -5, 0}}), 0)
+	e := newMatrix(t, 2, 0)
+	if v, _ := e.MaxRows().Get(1, 0); !math.IsInf(v, -1) {
+		t.Errorf("Got maximum %g of empty row, want -Inf", v)
+	}
+	if v, _ := e.MinRows().Get(1, 0); !math.IsInf(v, 1) {
+		t.Errorf("Got minimum %g of empty row, want +Inf", v)
+	}
+}
+
+func TestDeterminantClosedForm(t *testing.T) {
+	for _, data := range [][][]float64{
+		{{6, 1, 1}, {4, -2, 5}, {2, 8, 7}},
+		{{0, 2, 1}, {1, 1, 1}, {4, 2, 5}},
+		{{1e-3, 7, -2}, {3.5, 0, 1}, {-4, 2, 9}},
+	} {
+		m := fromSlice(t, data)
+		d, err := m.Determinant()
+		if err != nil {
+			t.Fatal(err)
+		}
+		bl, _ := matrix.Block([][]*matrix.Matrix{{m, newMatrix(t, 3, 1)}, {newMatrix(t, 1, 3), fromSlice(t, [][]float64{{1}})}})
+		want, err := bl.Determinant()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(d-want) > 1e-12*math.Abs(want) {
+			t.Errorf("Got %g with closed-form formula, want %g with LU decomposition", d, want)
+		}
+	}
+	a, b, c, e := 0.1, 0.2, 0.3, 0.4
+	if d, _ := fromSlice(t, [][]float64{{a, b}, {c, e}}).Determinant(); d != a*e-b*c {
+		t.Errorf("Got %g, want exact %g", d, a*e-b*c)
+	}
+}
+
+func TestSolveTriangular(t *testing.T) {
+	l := fromSlice(t, [][]float64{{2, 0, 0}, {1, 3, 0}, {-1, 4, 5}})
+	b := fromSlice(t, [][]float64{{2, 4}, {7, 2}, {18, -3}})
+	x, err := l.SolveLower(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, _ := l.Mul(x)
+	matrixtest.AssertEqual(t, r, b, 1e-12)
+	u := l.T()
+	x, err = u.SolveUpper(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, _ = u.Mul(x)
+	matrixtest.AssertEqual(t, r, b, 1e-12)
+	if _, err := fromSlice(t, [][]float64{{1, 0}, {1, 0}}).SolveLower(newMatrix(t, 2, 1)); !errors.Is(err, matrix.ErrSingular) {
+		t.Errorf("Got error %v, want %v", err, matrix.ErrSingular)
+	}
+	if _, err := l.SolveUpper(newMatrix(t, 2, 1)); err == nil {
+		t.Error("Expected error for incompatible dimentions")
+	}
+	if _, err := newMatrix(t, 2, 3).SolveLower(newMatrix(t, 2, 1)); err == nil {
+		t.Error("Expected error for not square matrix")
+	}
+}
+
+func TestIsTriangular(t *testing.T) {
+	tests := []struct {
+		name         string
+		m            *matrix.Matrix
+		upper, lower bool
+	}{
+		{"upper", fromSlice(t, [][]float64{{1, 2}, {0, 3}}), true, false},
+		{"lower", fromSlice(t, [][]float64{{1, 0}, {2, 3}}), false, true},
+		{"diagonal", matrix.Diagonal([]float64{1, 2}), true, true},
+		{"tolerance", fromSlice(t, [][]float64{{1, 1e-12}, {-1e-12, 3}}), true, true},
+		{"full", fromSlice(t, [][]float64{{1, 2}, {3, 4}}), false, false},
+		{"not square", newMatrix(t, 2, 3), false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.m.IsUpperTriangular(1e-9); got != tt.upper {
+				t.Errorf("Got upper triangular %t, want %t", got, tt.upper)
+			}
+			if got := tt.m.IsLowerTriangular(1e-9); got != tt.lower {
+				t.Errorf("Got lower triangular %t, want %t", got, tt.lower)
+			}
+		})
+	}
+}
+
+func TestScaled(t *testing.T) {
+	m := fromSlice(t, [][]float64{{1, -2}, {0, 3}})
+	matrixtest.AssertEqual(t, m.Scaled(-2), fromSlice(t, [][]float64{{-2, 4}, {0, -6}}), 0)
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{1, -2}, {0, 3}}), 0)
+}
+
+func TestPercentile(t *testing.T) {
+	m := fromSlice(t, [][]float64{{7, 1, 3}, {9, 5, 11}})
+	tests := []struct {
+		p, want float64
+	}{
+		{0, 1},
+		{100, 11},
+		{50, 6},
+		{20, 3},
+		{90, 10},
+	}
+	for _, tt := range tests {
+		if v, err := m.Percentile(tt.p); err != nil || math.Abs(v-tt.want) > 1e-12 {
+			t.Errorf("Got %g (%v) for percentile %g, want %g", v, err, tt.p, tt.want)
+		}
+	}
+	if v, _ := fromSlice(t, [][]float64{{4, -1, 2}}).Median(); v != 2 {
+		t.Errorf("Got median %g, want 2", v)
+	}
+	if v, _ := m.Median(); v != 6 {
+		t.Errorf("Got median %g, want 6", v)
+	}
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{7, 1, 3}, {9, 5, 11}}), 0)
+	for _, p := range []float64{-1, 101, math.NaN()} {
+		if _, err := m.Percentile(p); err == nil {
+			t.Errorf("Expected error for percentile %g", p)
+		}
+	}
+	if _, err := newMatrix(t, 0, 0).Median(); !errors.Is(err, matrix.ErrEmpty) {
+		t.Errorf("Got error %v, want %v", err, matrix.ErrEmpty)
+	}
+}
+
+func TestVariance(t *testing.T) {
+	m := fromSlice(t, [][]float64{{2, 4, 4, 4}, {5, 5, 7, 9}})
+	if v := m.Variance(); v != 4 {
+		t.Errorf("Got variance %g, want 4", v)
+	}
+	if v := m.StdDev(); v != 2 {
+		t.Errorf("Got standard deviation %g, want 2", v)
+	}
+	if v := fromSlice(t, [][]float64{{3, 3}}).Variance(); v != 0 {
+		t.Errorf("Got variance %g of constant matrix, want 0", v)
+	}
+	if v := newMatrix(t, 0, 0).Variance(); v != 0 {
+		t.Errorf("Got variance %g of empty matrix, want 0", v)
+	}
+}
+
+func TestStandardize(t *testing.T) {
+	m := fromSlice(t, [][]float64{{2, 4, 4, 4}, {5, 5, 7, 9}})
+	m.Standardize()
+	if mean, std := m.Mean(), m.StdDev(); math.Abs(mean) > 1e-15 || math.Abs(std-1) > 1e-15 {
+		t.Errorf("Got mean %g and standard deviation %g, want 0 and 1", mean, std)
+	}
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{-1.5, -0.5, -0.5, -0.5}, {0, 0, 1, 2}}), 1e-15)
+	c := fromSlice(t, [][]float64{{3, 3}})
+	c.Standardize()
+	matrixtest.AssertEqual(t, c, fromSlice(t, [][]float64{{3, 3}}), 0)
+}
+
+func TestStandardizeCols(t *testing.T) {
+	m := fromSlice(t, [][]float64{{1, 5, 10}, {3, 5, 20}, {5, 5, 30}})
+	m.StandardizeCols()
+	s := math.Sqrt(1.5)
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{-s, 5, -s}, {0, 5, 0}, {s, 5, s}}), 1e-15)
+}
+
+func TestDiff(t *testing.T) {
+	a := fromSlice(t, [][]float64{{1, 2}, {3, math.NaN()}})
+	b := fromSlice(t, [][]float64{{1, 2.05}, {4, math.NaN()}})
+	d, err := a.Diff(b, 0.1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d) != 2 || d[0] != (matrix.DiffEntry{I: 1, J: 0, Got: 3, Want: 4}) || d[1].I != 1 || d[1].J != 1 {
+		t.Errorf("Got %v, want entries at (1, 0) and (1, 1)", d)
+	}
+	if d, _ := a.Diff(a, 0); len(d) != 1 {
+		t.Errorf("Got %d entries comparing the matrix with itself, want 1 for NaN", len(d))
+	}
+	if d, _ := b.Diff(b.Scaled(1.01), 0.1); len(d) != 1 {
+		t.Errorf("Got %v, want only NaN entry", d)
+	}
+	if _, err := a.Diff(newMatrix(t, 2, 3), 0); err == nil {
+		t.Error("Expected error for mismatched dimentions")
+	}
+}
+
+func TestRank1Update(t *testing.T) {
+	m := fromSlice(t, [][]float64{{1, 2, 3}, {4, 5, 6}})
+	x := fromSlice(t, [][]float64{{1}, {-2}})
+	y := fromSlice(t, [][]float64{{3}, {0}, {0.5}})
+	o, _ := x.Outer(y)
+	want, _ := m.Plus(o.Scaled(-1.5))
+	if err := m.Rank1Update(-1.5, x, y); err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, m, want, 0)
+	if err := m.Rank1Update(1, y, x); err == nil {
+		t.Error("Expected error for incompatible dimentions")
+	}
+	if err := m.Rank1Update(1, x.T(), y); err == nil {
+		t.Error("Expected error for not column vector")
+	}
+	matrixtest.AssertEqual(t, m, want, 0)
+}
+
+func TestIdentity(t *testing.T) {
+	m, err := matrix.Identity(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}), 0)
+	m, err = matrix.Identity(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, m, newMatrix(t, 0, 0), 0)
+	if _, err := matrix.Identity(-1); err == nil {
+		t.Error("Expected error for negative size")
+	}
+}
+
+func TestInverseScaled(t *testing.T) {
+	for _, s := range []float64{1e-7, 1e-20, 1e20} {
+		m := fromSlice(t, [][]float64{{2 * s, s}, {s, 3 * s}})
+		inv, err := m.Inverse()
+		if err != nil {
+			t.Fatalf("Got error %v for matrix scaled by %g", err, s)
+		}
+		r, _ := m.Mul(inv)
+		id, _ := matrix.Identity(2)
+		matrixtest.AssertEqual(t, r, id, 1e-12)
+		if _, _, _, err := m.LU(); err != nil {
+			t.Errorf("Got error %v of LU for matrix scaled by %g", err, s)
+		}
+		if _, err := m.Solve(id); err != nil {
+			t.Errorf("Got error %v of Solve for matrix scaled by %g", err, s)
+		}
+	}
+	for _, data := range [][][]float64{{{1e-7, 2e-7}, {2e-7, 4e-7}}, {{0, 0}, {0, 0}}} {
+		if _, err := fromSlice(t, data).Inverse(); !errors.Is(err, matrix.ErrSingular) {
+			t.Errorf("Got error %v for %v, want %v", err, data, matrix.ErrSingular)
+		}
+	}
+}
+
+func TestHadamard(t *testing.T) {
+	m := fromSlice(t, [][]float64{{1, -2, 3}, {4, 0, 6}})
+	if err := m.Hadamard(fromSlice(t, [][]float64{{2, 3, 0.5}, {-1, 7, 0}})); err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{2, -6, 1.5}, {-4, 0, 0}}), 0)
+}
+
+func TestHadamardDimentions(t *testing.T) {
+	m := fromSlice(t, [][]float64{{1, 2, 3}, {4, 5, 6}})
+	if err := m.Hadamard(m.T()); err == nil {
+		t.Error("Expected error for mismatched dimentions")
+	}
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{1, 2, 3}, {4, 5, 6}}), 0)
+}
+
+func TestMean(t *testing.T) {
+	if v := fromSlice(t, [][]float64{{1, 2, 3}, {4, 5, -3}}).Mean(); v != 2 {
+		t.Errorf("Got mean %g, want 2", v)
+	}
+	if v := newMatrix(t, 0, 0).Mean(); v != 0 {
+		t.Errorf("Got mean %g of empty matrix, want 0", v)
+	}
+}
+
+func TestSwapRows(t *testing.T) {
+	m := fromSlice(t, [][]float64{{1, 2}, {3, 4}, {5, 6}})
+	if err := m.SwapRows(0, 2); err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{5, 6}, {3, 4}, {1, 2}}), 0)
+	if err := m.SwapRows(1, 1); err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{5, 6}, {3, 4}, {1, 2}}), 0)
+	for _, k := range []int{-1, 3} {
+		if err := m.SwapRows(0, k); err == nil {
+			t.Errorf("Expected error for out of range row %d", k)
+		}
+	}
+}
+
+func TestSwapCols(t *testing.T) {
+	m := fromSlice(t, [][]float64{{1, 2, 3}, {4, 5, 6}})
+	if err := m.SwapCols(2, 0); err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{3, 2, 1}, {6, 5, 4}}), 0)
+	if err := m.SwapCols(1, 1); err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{3, 2, 1}, {6, 5, 4}}), 0)
+	for _, k := range []int{-1, 3} {
+		if err := m.SwapCols(k, 0); err == nil {
+			t.Errorf("Expected error for out of range column %d", k)
+		}
+	}
+}
+
+func TestNegate(t *testing.T) {
+	m := fromSlice(t, [][]float64{{1, -2}, {0, 3.5}})
+	m.Negate()
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{-1, 2}, {0, -3.5}}), 0)
+	if v, _ := m.Get(1, 0); !math.Signbit(v) {
+		t.Errorf("Got %g for negated zero, want -0", v)
+	}
+	e := newMatrix(t, 0, 0)
+	e.Negate()
+	matrixtest.AssertEqual(t, e, newMatrix(t, 0, 0), 0)
+}
+
+func TestLaTeX(t *testing.T) {
+	m := fromSlice(t, [][]float64{{1, -2.5}, {1.0 / 3, 0}})
+	tests := []struct {
+		name, got, want string
+	}{
+		{"default", m.LaTeX(), "\\begin{pmatrix}\n1.000 & -2.500 \\\\\n0.333 & 0.000 \\\\\n\\end{pmatrix}"},
+		{"precision", m.LaTeXPrecision(1), "\\begin{pmatrix}\n1.0 & -2.5 \\\\\n0.3 & 0.0 \\\\\n\\end{pmatrix}"},
+		{"empty", newMatrix(t, 0, 0).LaTeX(), "\\begin{pmatrix}\n\\end{pmatrix}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("Got %q, want %q", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlusMinus(t *testing.T) {
+	a := fromSlice(t, [][]float64{{1, 2}, {3, 4}})
+	b := fromSlice(t, [][]float64{{5, -1}, {0.5, 4}})
+	p, err := a.Plus(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, p, fromSlice(t, [][]float64{{6, 1}, {3.5, 8}}), 0)
+	d, err := a.Minus(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, d, fromSlice(t, [][]float64{{-4, 3}, {2.5, 0}}), 0)
+	matrixtest.AssertEqual(t, a, fromSlice(t, [][]float64{{1, 2}, {3, 4}}), 0)
+	matrixtest.AssertEqual(t, b, fromSlice(t, [][]float64{{5, -1}, {0.5, 4}}), 0)
+	if _, err := a.Plus(newMatrix(t, 2, 3)); err == nil {
+		t.Error("Expected error for mismatched dimentions")
+	}
+	if _, err := a.Minus(newMatrix(t, 3, 2)); err == nil {
+		t.Error("Expected error for mismatched dimentions")
+	}
+}
+
+func TestDiv(t *testing.T) {
+	m := fromSlice(t, [][]float64{{6, -3}, {1, 2}})
+	if err := m.Div(fromSlice(t, [][]float64{{2, 3}, {4, -0.5}})); err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{3, -1}, {0.25, -4}}), 0)
+	if err := m.Div(newMatrix(t, 2, 3)); err == nil {
+		t.Error("Expected error for mismatched dimentions")
+	}
+}
+
+func TestDivZero(t *testing.T) {
+	m := fromSlice(t, [][]float64{{1, -1, 0}})
+	if err := m.Div(newMatrix(t, 1, 3)); err != nil {
+		t.Fatal(err)
+	}
+	a, _ := m.Get(0, 0)
+	b, _ := m.Get(0, 1)
+	c, _ := m.Get(0, 2)
+	if !math.IsInf(a, 1) || !math.IsInf(b, -1) || !math.IsNaN(c) {
+		t.Errorf("Got %g, %g, %g, want +Inf, -Inf, NaN", a, b, c)
+	}
+}
+
+func TestDivScalar(t *testing.T) {
+	m := fromSlice(t, [][]float64{{6, -3}, {1, 0}})
+	if err := m.DivScalar(-2); err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{-3, 1.5}, {-0.5, 0}}), 0)
+	if err := m.DivScalar(0); err == nil {
+		t.Error("Expected error for division by zero")
+	}
+	matrixtest.AssertEqual(t, m, fromSlice(t, [][]float64{{-3, 1.5}, {-0.5, 0}}), 0)
+}
+
+func TestMulTiled(t *testing.T) {
+	src := rand.New(rand.NewSource(1))
+	for _, d := range [][3]int{{1, 1, 1}, {3, 5, 2}, {70, 33, 129}, {130, 140, 150}} {
+		a, _ := matrix.Random(d[0], d[1], src)
+		b, _ := matrix.Random(d[1], d[2], src)
+		want, _ := a.Mul(b)
+		for _, tile := range []int{1, 7, 64, 200} {
+			r, err := a.MulTiled(b, tile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			matrixtest.AssertEqual(t, r, want, 1e-12)
+		}
+	}
+	a := newMatrix(t, 2, 3)
+	if _, err := a.MulTiled(a, 64); err == nil {
+		t.Error("Expected error for incompatible dimentions")
+	}
+	if _, err := a.MulTiled(a.T(), 0); err == nil {
+		t.Error("Expected error for non-positive tile size")
+	}
+}
+
+// testMulConcurrent runs multiplications in opposite directions
+// while other goroutines are waiting to write into both matrices
+func testMulConcurrent(t *testing.T, mul func(a, x *matrix.Matrix) (*matrix.Matrix, error)) {
+	a, b := newMatrix(t, 20, 20), newMatrix(t, 20, 20)
+	var wg sync.WaitGroup
+	for _, p := range [][2]*matrix.Matrix{{a, b}, {b, a}} {
+		wg.Add(2)
+		go func(m, x *matrix.Matrix) {
+			defer wg.Done()
+			for k := 0; k < 1000; k++ {
+				if _, err := mul(m, x); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}(p[0], p[1])
+		go func(m *matrix.Matrix) {
+			defer wg.Done()
+			for k := 0; k < 2000; k++ {
+				m.Set(k%20, k/20%20, float64(k))
+			}
+		}(p[0])
+	}
+	wg.Wait()
+}
+
+func TestMulTiledConcurrent(t *testing.T) {
+	testMulConcurrent(t, func(a, x *matrix.Matrix) (*matrix.Matrix, error) {
+		return a.MulTiled(x, 32)
+	})
+}
+
+func TestMulParallelConcurrent(t *testing.T) {
+	testMulConcurrent(t, (*matrix.Matrix).MulParallel)
+}
+
+// computedSymmetric returns symmetric positive definite matrix a*d*a.T() with large elements
+// which is symmetric only up to rounding errors of multiplication
+func computedSymmetric(t testing.TB) *matrix.Matrix {
+	t.Helper()
+	a, _ := matrix.Random(4, 4, rand.New(rand.NewSource(1)))
+	a.Scale(1e3)
+	ad, _ := a.Mul(matrix.Diagonal([]float64{1.1, 2.3, 3.7, 4.9}))
+	m, _ := ad.Mul(a.T())
+	if m.IsSymmetric(1e-12) || !m.IsSymmetric(1e-6) {
+		t.Fatal("Expected matrix symmetric only up to rounding errors")
+	}
+	return m
+}
+
+func TestCholeskyComputed(t *testing.T) {
+	m := computedSymmetric(t)
+	l, err := m.Cholesky()
+	if err != nil {
+		t.Fatal(err)
+	}
+	llt, _ := l.Mul(l.T())
+	matrixtest.AssertEqual(t, llt, m, 1e-6)
+}
+
+func TestEigenSymmetricComputed(t *testing.T) {
+	m := computedSymmetric(t)
+	values, q, err := m.EigenSymmetric()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mq, _ := m.Mul(q)
+	for j, v := range values {
+		for i := 0; i < 4; i++ {
+			got, _ := mq.Get(i, j)
+			want, _ := q.Get(i, j)
+			if math.Abs(got-v*want) > 1e-6 {
+				t.Errorf("Got %g at (%d, %d) of m*q, want %g", got, i, j, v*want)
+			}
+		}
+	}
+}
+
+func TestPowerRealComputed(t *testing.T) {
+	m := computedSymmetric(t)
+	s, err := m.PowerReal(0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ss, _ := s.Mul(s)
+	matrixtest.AssertEqual(t, ss, m, 1e-6)
+}
+
+func TestLDLComputed(t *testing.T) {
+	m := computedSymmetric(t)
+	l, d, err := m.LDL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ld, _ := l.Mul(d)
+	r, _ := ld.Mul(l.T())
+	matrixtest.AssertEqual(t, r, m, 1e-6)
+	_, _, err = newMatrix(t, 2, 3).LDL()
+	if err == nil || errors.Is(err, matrix.ErrNotSymmetric) {
+		t.Errorf("Got error %v for not square matrix, want error about dimentions", err)
+	}
+}
+
+func TestExp(t *testing.T) {
+	r, err := newMatrix(t, 3, 3).Exp()
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, _ := matrix.Identity(3)
+	matrixtest.AssertEqual(t, r, id, 0)
+	values := []float64{1, -2, 0.5, 10}
+	r, err = matrix.Diagonal(values).Exp()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range values {
+		values[k] = math.Exp(v)
+	}
+	want := matrix.Diagonal(values)
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			got, _ := r.Get(i, j)
+			w, _ := want.Get(i, j)
+			if math.Abs(got-w) > 1e-12*math.Max(1, w) {
+				t.Errorf("Got %g at (%d, %d), want %g", got, i, j, w)
+			}
+		}
+	}
+	if _, err := newMatrix(t, 2, 3).Exp(); err == nil {
+		t.Error("Expected error for not square matrix")
+	}
+}
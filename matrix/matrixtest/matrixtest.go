@@ -0,0 +1,36 @@
+// Package matrixtest implements helpers for testing code which uses matrices
+package matrixtest
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/andreipimenov/algebra/matrix"
+)
+
+// AssertEqual fails the test when matrices have different dimentions
+// or elements which differ by more than given absolute tolerance
+func AssertEqual(t testing.TB, got, want *matrix.Matrix, tol float64) {
+	t.Helper()
+	gr, gc := got.Dimentions()
+	wr, wc := want.Dimentions()
+	if gr != wr || gc != wc {
+		t.Errorf("Dimentions %dx%d are not equal to expected %dx%d\ngot:\n%swant:\n%s", gr, gc, wr, wc, got.String(), want.String())
+		return
+	}
+	b := &bytes.Buffer{}
+	for i := 0; i < gr; i++ {
+		for j := 0; j < gc; j++ {
+			g, _ := got.Get(i, j)
+			w, _ := want.Get(i, j)
+			if !(math.Abs(g-w) <= tol) {
+				fmt.Fprintf(b, "(%d, %d): got %g, want %g\n", i, j, g, w)
+			}
+		}
+	}
+	if b.Len() > 0 {
+		t.Errorf("Matrices differ by more than %g at positions:\n%sgot:\n%swant:\n%s", tol, b.String(), got.String(), want.String())
+	}
+}
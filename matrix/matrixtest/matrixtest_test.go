@@ -0,0 +1,66 @@
+package matrixtest_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/andreipimenov/algebra/matrix"
+	"github.com/andreipimenov/algebra/matrix/matrixtest"
+)
+
+// recorder is testing.TB which records failures instead of failing the test
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func fromSlice(t *testing.T, data [][]float64) *matrix.Matrix {
+	t.Helper()
+	m, err := matrix.FromSlice(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestAssertEqual(t *testing.T) {
+	want := fromSlice(t, [][]float64{{1, 2}, {3, 4}})
+	tests := []struct {
+		name     string
+		got      *matrix.Matrix
+		tol      float64
+		messages []string
+	}{
+		{"equal", fromSlice(t, [][]float64{{1, 2}, {3, 4}}), 0, nil},
+		{"within tolerance", fromSlice(t, [][]float64{{1, 2}, {3, 4.05}}), 0.1, nil},
+		{"value", fromSlice(t, [][]float64{{1, 2}, {5, 4}}), 0.1, []string{"(1, 0): got 5, want 3"}},
+		{"shape", fromSlice(t, [][]float64{{1, 2, 3}, {3, 4, 5}}), 0, []string{"Dimentions 2x3 are not equal to expected 2x2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recorder{TB: t}
+			matrixtest.AssertEqual(r, tt.got, want, tt.tol)
+			if tt.messages == nil {
+				if len(r.errors) != 0 {
+					t.Errorf("Got unexpected failure %q", r.errors)
+				}
+				return
+			}
+			if len(r.errors) != 1 {
+				t.Fatalf("Got %d failures, want 1", len(r.errors))
+			}
+			for _, s := range tt.messages {
+				if !strings.Contains(r.errors[0], s) {
+					t.Errorf("Failure %q does not contain %q", r.errors[0], s)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package matrix
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func benchmarkMul(b *testing.B, mul func(a, x *Matrix) *Matrix) {
+	src := rand.New(rand.NewSource(1))
+	a, _ := Random(1024, 1024, src)
+	x, _ := Random(1024, 1024, src)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		mul(a, x)
+	}
+}
+
+func BenchmarkMulNaive(b *testing.B) {
+	benchmarkMul(b, (*Matrix).mulNaive)
+}
+
+func BenchmarkMulTiled(b *testing.B) {
+	benchmarkMul(b, func(a, x *Matrix) *Matrix {
+		r, _ := a.MulTiled(x, tileSize)
+		return r
+	})
+}
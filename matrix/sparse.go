@@ -0,0 +1,274 @@
+package matrix
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Matrixlike is an interface for reading and writing elements
+// which is implemented by both dense and sparse matrices
+type Matrixlike interface {
+	Dimentions() (int, int)
+	Get(i, j int) (float64, error)
+	Set(i, j int, v float64) error
+}
+
+// SparseMatrix is a type for 2-dimentional matrices
+// which stores only non-zero elements in the map keyed by position in row-major order
+type SparseMatrix struct {
+	rows int
+	cols int
+	data map[int]float64
+	sync.RWMutex
+}
+
+// NewSparse returns pointer to the new empty sparse matrix with given dimentions
+func NewSparse(rows, cols int) (*SparseMatrix, error) {
+	if rows < 0 || cols < 0 {
+		return nil, fmt.Errorf("Dimetions %dx%d must not being negative", rows, cols)
+	}
+	return &SparseMatrix{
+		rows: rows,
+		cols: cols,
+		data: make(map[int]float64),
+	}, nil
+}
+
+// Dimentions returns count of rows and columns of the matrix
+func (m *SparseMatrix) Dimentions() (int, int) {
+	return m.rows, m.cols
+}
+
+// NonZero returns count of stored non-zero elements of the matrix
+func (m *SparseMatrix) NonZero() int {
+	m.RLock()
+	defer m.RUnlock()
+	return len(m.data)
+}
+
+func (m *SparseMatrix) checkRange(i, j int) error {
+	if i < 0 || j < 0 {
+		return fmt.Errorf("Position (%d, %d) must not being negative", i, j)
+	}
+	if i >= m.rows || j >= m.cols {
+		return fmt.Errorf("Position (%d, %d) is out of the range (0:%d, 0:%d)", i, j, m.rows-1, m.cols-1)
+	}
+	return nil
+}
+
+func (m *SparseMatrix) get(i, j int) float64 {
+	m.RLock()
+	defer m.RUnlock()
+	return m.data[m.cols*i+j]
+}
+
+func (m *SparseMatrix) set(i, j int, v float64) {
+	m.Lock()
+	if v == 0 {
+		delete(m.data, m.cols*i+j)
+	} else {
+		m.data[m.cols*i+j] = v
+	}
+	m.Unlock()
+}
+
+// Get returns the value of (i, j)
+func (m *SparseMatrix) Get(i, j int) (float64, error) {
+	if err := m.checkRange(i, j); err != nil {
+		return 0, err
+	}
+	return m.get(i, j), nil
+}
+
+// Set sets the value at (i, j)
+func (m *SparseMatrix) Set(i, j int, v float64) error {
+	if err := m.checkRange(i, j); err != nil {
+		return err
+	}
+	m.set(i, j, v)
+	return nil
+}
+
+// T returns new transposed sparse matrix
+func (m *SparseMatrix) T() *SparseMatrix {
+	t := &SparseMatrix{
+		rows: m.cols,
+		cols: m.rows,
+		data: make(map[int]float64),
+	}
+	m.RLock()
+	defer m.RUnlock()
+	for k, v := range m.data {
+		i, j := k/m.cols, k%m.cols
+		t.data[t.cols*j+i] = v
+	}
+	return t
+}
+
+// Add adds dense or sparse matrix
+func (m *SparseMatrix) Add(x Matrixlike) error {
+	rows, cols := x.Dimentions()
+	if m.rows != rows || m.cols != cols {
+		return fmt.Errorf("Dimentions of two matrices %dx%d and %dx%d are not equal", m.rows, m.cols, rows, cols)
+	}
+	if s, ok := x.(*SparseMatrix); ok {
+		for k, v := range s.Clone().data {
+			m.set(k/cols, k%cols, m.get(k/cols, k%cols)+v)
+		}
+		return nil
+	}
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			v, _ := x.Get(i, j)
+			m.set(i, j, m.get(i, j)+v)
+		}
+	}
+	return nil
+}
+
+// Mul returns new sparse matrix which is the product of the matrix and given dense or sparse one
+func (m *SparseMatrix) Mul(x Matrixlike) (*SparseMatrix, error) {
+	rows, cols := x.Dimentions()
+	if m.cols != rows {
+		return nil, fmt.Errorf("Dimentions of two matrices %dx%d and %dx%d are not compatible for multiplication", m.rows, m.cols, rows, cols)
+	}
+	r := &SparseMatrix{
+		rows: m.rows,
+		cols: cols,
+		data: make(map[int]float64),
+	}
+	a := m.Clone().data
+	if s, ok := x.(*SparseMatrix); ok {
+		byRow := make(map[int]map[int]float64)
+		for k, v := range s.Clone().data {
+			if byRow[k/cols] == nil {
+				byRow[k/cols] = make(map[int]float64)
+			}
+			byRow[k/cols][k%cols] = v
+		}
+		for k, u := range a {
+			i, p := k/m.cols, k%m.cols
+			for j, v := range byRow[p] {
+				r.data[cols*i+j] += u * v
+			}
+		}
+	} else {
+		for k, u := range a {
+			i, p := k/m.cols, k%m.cols
+			for j := 0; j < cols; j++ {
+				v, _ := x.Get(p, j)
+				r.data[cols*i+j] += u * v
+			}
+		}
+	}
+	for k, v := range r.data {
+		if v == 0 {
+			delete(r.data, k)
+		}
+	}
+	return r, nil
+}
+
+// Transpose returns new transposed matrix of the same form as given dense or sparse one,
+// other implementations of Matrixlike are transposed into the new dense matrix
+func Transpose(x Matrixlike) Matrixlike {
+	switch m := x.(type) {
+	case *Matrix:
+		return m.T()
+	case *SparseMatrix:
+		return m.T()
+	}
+	rows, cols := x.Dimentions()
+	t := &Matrix{
+		rows: cols,
+		cols: rows,
+		data: make([]float64, rows*cols),
+	}
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			t.data[rows*j+i], _ = x.Get(i, j)
+		}
+	}
+	return t
+}
+
+// AddSparse adds sparse matrix touching only its non-zero elements
+func (m *Matrix) AddSparse(x *SparseMatrix) error {
+	if m.rows != x.rows || m.cols != x.cols {
+		return fmt.Errorf("Dimentions of two matrices %dx%d and %dx%d are not equal", m.rows, m.cols, x.rows, x.cols)
+	}
+	b := x.Clone().data
+	m.Lock()
+	defer m.Unlock()
+	for k, v := range b {
+		m.data[k] += v
+	}
+	return nil
+}
+
+// MulSparse returns new dense matrix which is the product of the matrix and given sparse one
+func (m *Matrix) MulSparse(x *SparseMatrix) (*Matrix, error) {
+	if m.cols != x.rows {
+		return nil, fmt.Errorf("Dimentions of two matrices %dx%d and %dx%d are not compatible for multiplication", m.rows, m.cols, x.rows, x.cols)
+	}
+	r := &Matrix{
+		rows: m.rows,
+		cols: x.cols,
+		data: make([]float64, m.rows*x.cols),
+	}
+	a, b := m.Clone().data, x.Clone().data
+	for k, v := range b {
+		p, j := k/x.cols, k%x.cols
+		for i := 0; i < m.rows; i++ {
+			r.data[r.cols*i+j] += a[m.cols*i+p] * v
+		}
+	}
+	return r, nil
+}
+
+// Clone returns new cloned sparse matrix
+func (m *SparseMatrix) Clone() *SparseMatrix {
+	c := &SparseMatrix{
+		rows: m.rows,
+		cols: m.cols,
+		data: make(map[int]float64),
+	}
+	m.RLock()
+	for k, v := range m.data {
+		c.data[k] = v
+	}
+	m.RUnlock()
+	return c
+}
+
+// ToDense returns new dense matrix with the same elements
+func (m *SparseMatrix) ToDense() *Matrix {
+	d := &Matrix{
+		rows: m.rows,
+		cols: m.cols,
+		data: make([]float64, m.rows*m.cols),
+	}
+	m.RLock()
+	for k, v := range m.data {
+		d.data[k] = v
+	}
+	m.RUnlock()
+	return d
+}
+
+// ToSparse returns new sparse matrix with the same elements
+func (m *Matrix) ToSparse() *SparseMatrix {
+	s := &SparseMatrix{
+		rows: m.rows,
+		cols: m.cols,
+		data: make(map[int]float64),
+	}
+	m.RLock()
+	for k, v := range m.data {
+		if v != 0 {
+			s.data[k] = v
+		}
+	}
+	m.RUnlock()
+	return s
+}
@@ -0,0 +1,139 @@
+package matrix_test
+
+import (
+	"testing"
+
+	"github.com/andreipimenov/algebra/matrix"
+	"github.com/andreipimenov/algebra/matrix/matrixtest"
+)
+
+func newSparse(t testing.TB, rows, cols int, entries ...matrix.Entry) *matrix.SparseMatrix {
+	t.Helper()
+	s, err := matrix.NewSparse(rows, cols)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if err := s.Set(e.I, e.J, e.V); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return s
+}
+
+func TestSparseSetGet(t *testing.T) {
+	s := newSparse(t, 2, 3, matrix.Entry{I: 0, J: 2, V: 5}, matrix.Entry{I: 1, J: 0, V: -1})
+	if v, _ := s.Get(0, 2); v != 5 {
+		t.Errorf("Got %g at (0, 2), want 5", v)
+	}
+	if v, _ := s.Get(1, 1); v != 0 {
+		t.Errorf("Got %g at (1, 1), want 0", v)
+	}
+	if s.NonZero() != 2 {
+		t.Errorf("Got %d non-zero elements, want 2", s.NonZero())
+	}
+	s.Set(0, 2, 0)
+	if s.NonZero() != 1 {
+		t.Errorf("Got %d non-zero elements after setting zero, want 1", s.NonZero())
+	}
+	if _, err := s.Get(2, 0); err == nil {
+		t.Error("Expected error for out of range position")
+	}
+}
+
+func TestSparseConversion(t *testing.T) {
+	m := fromSlice(t, [][]float64{{0, 2, 0}, {3, 0, 0}})
+	s := m.ToSparse()
+	if s.NonZero() != 2 {
+		t.Errorf("Got %d non-zero elements, want 2", s.NonZero())
+	}
+	matrixtest.AssertEqual(t, s.ToDense(), m, 0)
+	matrixtest.AssertEqual(t, s.T().ToDense(), m.T(), 0)
+}
+
+func TestSparseAdd(t *testing.T) {
+	s := newSparse(t, 2, 2, matrix.Entry{I: 0, J: 1, V: 2})
+	if err := s.Add(newSparse(t, 2, 2, matrix.Entry{I: 0, J: 1, V: -2}, matrix.Entry{I: 1, J: 1, V: 1})); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Add(fromSlice(t, [][]float64{{1, 0}, {0, 0}})); err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, s.ToDense(), fromSlice(t, [][]float64{{1, 0}, {0, 1}}), 0)
+	if s.NonZero() != 2 {
+		t.Errorf("Got %d non-zero elements, want 2", s.NonZero())
+	}
+	if err := s.Add(newMatrix(t, 2, 3)); err == nil {
+		t.Error("Expected error for mismatched dimentions")
+	}
+}
+
+func TestSparseMul(t *testing.T) {
+	a := fromSlice(t, [][]float64{{1, 0, 2}, {0, 3, 0}})
+	b := fromSlice(t, [][]float64{{0, 1}, {4, 0}, {0, 5}})
+	want, _ := a.Mul(b)
+	for _, x := range []matrix.Matrixlike{b, b.ToSparse()} {
+		r, err := a.ToSparse().Mul(x)
+		if err != nil {
+			t.Fatal(err)
+		}
+		matrixtest.AssertEqual(t, r.ToDense(), want, 0)
+	}
+	if _, err := a.ToSparse().Mul(a); err == nil {
+		t.Error("Expected error for incompatible dimentions")
+	}
+}
+
+func TestDenseSparse(t *testing.T) {
+	a := fromSlice(t, [][]float64{{1, 2, 3}, {4, 5, 6}})
+	s := newSparse(t, 3, 2, matrix.Entry{I: 0, J: 1, V: 2}, matrix.Entry{I: 2, J: 0, V: -1})
+	r, err := a.MulSparse(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := a.Mul(s.ToDense())
+	matrixtest.AssertEqual(t, r, want, 0)
+	if _, err := a.MulSparse(s.T()); err == nil {
+		t.Error("Expected error for incompatible dimentions")
+	}
+	if err := a.AddSparse(s.T()); err != nil {
+		t.Fatal(err)
+	}
+	matrixtest.AssertEqual(t, a, fromSlice(t, [][]float64{{1, 2, 2}, {6, 5, 6}}), 0)
+	if err := a.AddSparse(s); err == nil {
+		t.Error("Expected error for mismatched dimentions")
+	}
+}
+
+func TestTranspose(t *testing.T) {
+	m := fromSlice(t, [][]float64{{1, 0, 2}, {0, 3, 0}})
+	if d, ok := matrix.Transpose(m).(*matrix.Matrix); !ok {
+		t.Error("Expected dense transposed matrix")
+	} else {
+		matrixtest.AssertEqual(t, d, m.T(), 0)
+	}
+	if s, ok := matrix.Transpose(m.ToSparse()).(*matrix.SparseMatrix); !ok {
+		t.Error("Expected sparse transposed matrix")
+	} else {
+		matrixtest.AssertEqual(t, s.ToDense(), m.T(), 0)
+	}
+}
+
+const diagonalSize = 10000
+
+func BenchmarkDiagonalDense(b *testing.B) {
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		matrix.Identity(diagonalSize)
+	}
+}
+
+func BenchmarkDiagonalSparse(b *testing.B) {
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		s, _ := matrix.NewSparse(diagonalSize, diagonalSize)
+		for i := 0; i < diagonalSize; i++ {
+			s.Set(i, i, 1)
+		}
+	}
+}
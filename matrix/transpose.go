@@ -0,0 +1,36 @@
+package matrix
+
+import "fmt"
+
+// TView is a read-only transposed view of the matrix which swaps indices
+// into the underlying matrix without copying its elements.
+// Writes are not supported, changes of the underlying matrix are visible through the view
+type TView struct {
+	m *Matrix
+}
+
+// TView returns new transposed view of the matrix
+func (m *Matrix) TView() *TView {
+	return &TView{m: m}
+}
+
+// Dimentions returns count of rows and columns of the transposed matrix
+func (t *TView) Dimentions() (int, int) {
+	return t.m.cols, t.m.rows
+}
+
+// Get returns the value of (i, j) of the transposed matrix
+func (t *TView) Get(i, j int) (float64, error) {
+	if i < 0 || j < 0 {
+		return 0, fmt.Errorf("Position (%d, %d) must not being negative", i, j)
+	}
+	if i >= t.m.cols || j >= t.m.rows {
+		return 0, fmt.Errorf("Position (%d, %d) is out of the range (0:%d, 0:%d)", i, j, t.m.cols-1, t.m.rows-1)
+	}
+	return t.m.get(j, i), nil
+}
+
+// String returns string representation of the transposed matrix
+func (t *TView) String() string {
+	return format(t.m.cols, t.m.rows, func(i, j int) float64 { return t.m.get(j, i) }, 3, 15)
+}
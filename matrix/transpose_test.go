@@ -0,0 +1,33 @@
+package matrix_test
+
+import "testing"
+
+func TestTView(t *testing.T) {
+	m := fromSlice(t, [][]float64{{1, 2, 3}, {4, 5, 6}})
+	v := m.TView()
+	rows, cols := v.Dimentions()
+	if rows != 3 || cols != 2 {
+		t.Fatalf("Got dimentions %dx%d, want 3x2", rows, cols)
+	}
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			got, err := v.Get(i, j)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want, _ := m.Get(j, i); got != want {
+				t.Errorf("Got %g at (%d, %d), want %g", got, i, j, want)
+			}
+		}
+	}
+	if _, err := v.Get(2, 2); err == nil {
+		t.Error("Expected error for out of range position")
+	}
+	if got, want := v.String(), m.T().String(); got != want {
+		t.Errorf("Got string\n%s\nwant\n%s", got, want)
+	}
+	m.Set(0, 1, 7)
+	if got, _ := v.Get(1, 0); got != 7 {
+		t.Errorf("Got %g after change of the matrix, want 7", got)
+	}
+}
@@ -0,0 +1,46 @@
+package matrix
+
+import "fmt"
+
+// Vector is a basic type for 1-dimentional vectors.
+// Vector can be turned into the matrix with one column by ColVec
+// or with one row by RowVec
+type Vector []float64
+
+// ColVec returns pointer to the new matrix with one column filled with values of the vector
+func ColVec(v Vector) *Matrix {
+	m := &Matrix{
+		rows: len(v),
+		cols: 1,
+		data: make([]float64, len(v)),
+	}
+	copy(m.data, v)
+	return m
+}
+
+// RowVec returns pointer to the new matrix with one row filled with values of the vector
+func RowVec(v Vector) *Matrix {
+	m := &Matrix{
+		rows: 1,
+		cols: len(v),
+		data: make([]float64, len(v)),
+	}
+	copy(m.data, v)
+	return m
+}
+
+// MulVec returns new vector which is the product of the matrix and given vector
+func (m *Matrix) MulVec(v Vector) (Vector, error) {
+	if m.cols != len(v) {
+		return nil, fmt.Errorf("Dimentions of matrix %dx%d and vector %d are not compatible for multiplication", m.rows, m.cols, len(v))
+	}
+	m.RLock()
+	defer m.RUnlock()
+	r := make(Vector, m.rows)
+	for i := 0; i < m.rows; i++ {
+		for j, x := range v {
+			r[i] += m.data[m.cols*i+j] * x
+		}
+	}
+	return r, nil
+}
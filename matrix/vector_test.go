@@ -0,0 +1,37 @@
+package matrix_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/andreipimenov/algebra/matrix"
+	"github.com/andreipimenov/algebra/matrix/matrixtest"
+)
+
+func TestMulVec(t *testing.T) {
+	m := fromSlice(t, [][]float64{{1, 2, 3}, {4, 5, 6}, {7, 8, 10}})
+	v := matrix.Vector{1, -1, 2}
+	r, err := m.MulVec(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(r, matrix.Vector{5, 11, 19}) {
+		t.Errorf("Got %v, want [5 11 19]", r)
+	}
+	want, _ := m.Mul(matrix.ColVec(v))
+	matrixtest.AssertEqual(t, matrix.ColVec(r), want, 0)
+	if _, err := m.MulVec(matrix.Vector{1, 2}); err == nil {
+		t.Error("Expected error for incompatible dimentions")
+	}
+}
+
+func TestColRowVec(t *testing.T) {
+	v := matrix.Vector{1, 2, 3}
+	c, r := matrix.ColVec(v), matrix.RowVec(v)
+	matrixtest.AssertEqual(t, c, fromSlice(t, [][]float64{{1}, {2}, {3}}), 0)
+	matrixtest.AssertEqual(t, r, fromSlice(t, [][]float64{{1, 2, 3}}), 0)
+	v[0] = 10
+	if x, _ := c.Get(0, 0); x != 1 {
+		t.Errorf("Got %g after change of the vector, want 1", x)
+	}
+}